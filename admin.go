@@ -0,0 +1,104 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: admin.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define utilitários administrativos da coleção, como
+	estatísticas de armazenamento para planejamento de capacidade.
+*/
+package monger
+
+import "context"
+
+// CollStats reúne as estatísticas mais comuns do comando collStats.
+// Tamanhos em bytes.
+type CollStats struct {
+	Namespace      string           `json:"ns"`
+	Count          int64            `json:"count"`
+	Size           int64            `json:"size"`
+	AvgObjSize     float64          `json:"avgObjSize"`
+	StorageSize    int64            `json:"storageSize"`
+	TotalIndexSize int64            `json:"totalIndexSize"`
+	IndexCount     int64            `json:"nindexes"`
+	IndexSizes     map[string]int64 `json:"indexSizes"`
+}
+
+// Stats retorna as estatísticas da coleção (quantidade de documentos, tamanho médio,
+// armazenamento e tamanho dos índices), a partir do comando collStats.
+//
+// Os campos numéricos são normalizados, pois o tipo retornado pelo servidor
+// (int32, int64 ou double) varia entre versões do MongoDB.
+//
+// Exemplo de uso:
+//
+//	stats, err := users.Stats(ctx)
+//	fmt.Println(stats.Count, stats.StorageSize, stats.IndexSizes["email_1"])
+func (r *Repository[T]) Stats(ctx context.Context) (stats *CollStats, err error) {
+	ctx, span := r.startSpan(ctx, "Stats")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	var raw M
+	err = r.coll.Database().RunCommand(ctx, D{{Key: "collStats", Value: r.coll.Name()}}).Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	stats = &CollStats{
+		Count:          asInt64(raw["count"]),
+		Size:           asInt64(raw["size"]),
+		AvgObjSize:     asFloat64(raw["avgObjSize"]),
+		StorageSize:    asInt64(raw["storageSize"]),
+		TotalIndexSize: asInt64(raw["totalIndexSize"]),
+		IndexCount:     asInt64(raw["nindexes"]),
+		IndexSizes:     map[string]int64{},
+	}
+	stats.Namespace, _ = raw["ns"].(string)
+	if sizes, ok := raw["indexSizes"].(M); ok {
+		for name, size := range sizes {
+			stats.IndexSizes[name] = asInt64(size)
+		}
+	}
+	return stats, nil
+}
+
+// asInt64 converte um número BSON (int32, int64 ou double) para int64; outros valores viram 0.
+func asInt64(v any) int64 {
+	switch n := v.(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+// asFloat64 converte um número BSON (int32, int64 ou double) para float64; outros valores viram 0.
+func asFloat64(v any) float64 {
+	switch n := v.(type) {
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
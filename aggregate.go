@@ -0,0 +1,762 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: aggregate.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o Pipeline (builder de estágios de agregação) e os
+	utilitários para executar agregações com resultado tipado.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- PIPELINE ---
+// Permite montar pipelines de agregação sem escrever []M manualmente
+type Pipeline struct {
+	stages []M
+}
+
+// AggregateBuilder é um nome alternativo para Pipeline, no padrão de FilterBuilder e ProjectBuilder.
+type AggregateBuilder = Pipeline
+
+// Aggregation cria um pipeline de agregação vazio (mesmo que NewPipeline).
+func Aggregation() *AggregateBuilder {
+	return NewPipeline()
+}
+
+// NewPipeline cria um pipeline de agregação vazio.
+func NewPipeline() *Pipeline {
+	return &Pipeline{stages: []M{}}
+}
+
+// Stage adiciona um estágio arbitrário ao pipeline (ex.: M{"$addFields": ...}).
+func (p *Pipeline) Stage(stage M) *Pipeline {
+	p.stages = append(p.stages, stage)
+	return p
+}
+
+// Match adiciona um estágio $match com o filtro (nil equivale a um filtro vazio).
+func (p *Pipeline) Match(f *FilterBuilder) *Pipeline {
+	filter := M{}
+	if f != nil {
+		filter = f.Build()
+	}
+	return p.Stage(M{"$match": filter})
+}
+
+// Group adiciona um estágio $group (ex.: M{"_id": "$status", "total": M{"$sum": "$amount"}}).
+func (p *Pipeline) Group(group M) *Pipeline {
+	return p.Stage(M{"$group": group})
+}
+
+// Sort adiciona um estágio $sort (use D para preservar a ordem das chaves).
+func (p *Pipeline) Sort(sort D) *Pipeline {
+	return p.Stage(M{"$sort": sort})
+}
+
+// Unwind adiciona um estágio $unwind, gerando um documento por elemento do array em path.
+// O prefixo "$" é opcional ("items" e "$items" são equivalentes).
+//
+// Se preserveNullAndEmpty for true, documentos em que o array é nulo, vazio ou ausente
+// também são mantidos no resultado (preserveNullAndEmptyArrays).
+//
+// Exemplo de uso:
+//
+//	// Uma linha por item de pedido
+//	p := monger.NewPipeline().Unwind("items", false)
+//	lines, err := monger.AggregateAs[OrderLine](ctx, orders, p.Build())
+func (p *Pipeline) Unwind(path string, preserveNullAndEmpty bool) *Pipeline {
+	return p.Stage(M{"$unwind": M{
+		"path":                       fieldRef(path),
+		"preserveNullAndEmptyArrays": preserveNullAndEmpty,
+	}})
+}
+
+// Project adiciona um estágio $project com a projeção (nil equivale a uma projeção vazia).
+func (p *Pipeline) Project(proj *ProjectBuilder) *Pipeline {
+	projection := M{}
+	if proj != nil {
+		projection = proj.Build()
+	}
+	return p.Stage(M{"$project": projection})
+}
+
+// Limit adiciona um estágio $limit.
+func (p *Pipeline) Limit(n int64) *Pipeline {
+	return p.Stage(M{"$limit": n})
+}
+
+// Skip adiciona um estágio $skip.
+func (p *Pipeline) Skip(n int64) *Pipeline {
+	return p.Stage(M{"$skip": n})
+}
+
+// Lookup adiciona um estágio $lookup: para cada documento, grava em as o array dos documentos
+// de from cujo foreignField é igual ao localField do documento.
+//
+// Exemplo de uso:
+//
+//	p := monger.Aggregation().
+//	    Match(monger.Filter().Eq("status", "paid")).
+//	    Lookup("customers", "customerId", "_id", "customer").
+//	    Unwind("customer", true)
+func (p *Pipeline) Lookup(from, localField, foreignField, as string) *Pipeline {
+	return p.Stage(M{"$lookup": M{
+		"from":         from,
+		"localField":   localField,
+		"foreignField": foreignField,
+		"as":           as,
+	}})
+}
+
+// Build retorna os estágios do pipeline prontos para uso no driver.
+func (p *Pipeline) Build() []M {
+	return p.stages
+}
+
+// Decode executa o pipeline na coleção do repositório e decodifica todos os documentos
+// resultantes em out (ponteiro para slice), terminando a cadeia de forma fluente. As mesmas
+// restrições de AggregateAs (WithRowSecurity, WithSoftDelete) são aplicadas.
+//
+// Exemplo de uso:
+//
+//	var report []SalesByRegion
+//	err := monger.NewPipeline().
+//	    Match(monger.Filter().Eq("status", "paid")).
+//	    Group(monger.M{"_id": "$region", "total": monger.M{"$sum": "$amount"}}).
+//	    Sort(monger.D{{Key: "total", Value: -1}}).
+//	    Decode(ctx, orders, &report)
+func (p *Pipeline) Decode(ctx context.Context, src aggregator, out any) error {
+	return src.Aggregate(ctx, p.Build(), out)
+}
+
+// Run executa o pipeline na coleção do repositório e decodifica o resultado em R.
+// É o equivalente tipado de Pipeline.Decode (métodos não podem ter parâmetros de tipo em Go).
+//
+// Exemplo de uso:
+//
+//	report, err := monger.Run[SalesByRegion](ctx, orders, monger.NewPipeline().
+//	    Match(monger.Filter().Eq("status", "paid")).
+//	    Group(monger.M{"_id": "$region", "total": monger.M{"$sum": "$amount"}}).
+//	    Sort(monger.D{{Key: "total", Value: -1}}))
+func Run[R any, T any](ctx context.Context, r *Repository[T], p *Pipeline) (out []R, err error) {
+	ctx, span := r.startSpan(ctx, "Run")
+	defer func() { span.end(err, int64(len(out))) }()
+	return aggregateAs[R](ctx, r, p.Build())
+}
+
+// aggregator é implementado por *Repository[T], permitindo que Pipeline.Decode receba
+// repositórios de qualquer tipo.
+type aggregator interface {
+	Aggregate(ctx context.Context, pipeline []M, out any) error
+}
+
+// aggregate executa um pipeline já com as restrições do repositório.
+func (r *Repository[T]) aggregate(ctx context.Context, pipeline []M) (*mongo.Cursor, error) {
+	pipeline, err := r.scopePipeline(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	r.logOp("Aggregate", func() M { return M{"pipeline": pipeline} })
+	return withRetry(ctx, &r.cfg, !writesOutput(pipeline), func() (*mongo.Cursor, error) {
+		return r.coll.Aggregate(ctx, pipeline, r.aggregateOpts())
+	})
+}
+
+// writesOutput indica se o pipeline termina em $out ou $merge: nesse caso a agregação é uma
+// escrita, que não é segura de repetir.
+func writesOutput(pipeline []M) bool {
+	if len(pipeline) == 0 {
+		return false
+	}
+	last := pipeline[len(pipeline)-1]
+	_, out := last["$out"]
+	_, merge := last["$merge"]
+	return out || merge
+}
+
+// aggregateOpts monta as opções padrão das agregações do repositório.
+func (r *Repository[T]) aggregateOpts() *options.AggregateOptions {
+	opts := options.Aggregate()
+	if r.cfg.allowDiskUse {
+		opts.SetAllowDiskUse(true)
+	}
+	return opts
+}
+
+// scopePipeline insere no início do pipeline um $match com as restrições obrigatórias
+// do repositório (ex.: WithRowSecurity). Sem restrições, o pipeline é retornado intacto.
+func (r *Repository[T]) scopePipeline(ctx context.Context, pipeline []M) ([]M, error) {
+	scope, err := r.scopeFilter(ctx, M{})
+	if err != nil {
+		return nil, err
+	}
+	if len(scope) == 0 {
+		return pipeline, nil
+	}
+	return append([]M{{"$match": scope}}, pipeline...), nil
+}
+
+// fieldRef garante o prefixo "$" para referências a campos em expressões de agregação.
+func fieldRef(field string) string {
+	if strings.HasPrefix(field, "$") {
+		return field
+	}
+	return "$" + field
+}
+
+// AggregateAs executa um pipeline de agregação na coleção do Repository e decodifica
+// cada documento resultante em R. Útil quando o formato do resultado difere do model T
+// (ex.: após $unwind, $group ou $project).
+//
+// Se o repositório tiver restrições obrigatórias (WithRowSecurity), um $match com elas é
+// inserido como primeiro estágio do pipeline.
+//
+// Exemplo de uso:
+//
+//	type OrderLine struct {
+//	    OrderID primitive.ObjectID `bson:"_id"`
+//	    Item    Item               `bson:"items"`
+//	}
+//
+//	lines, err := monger.AggregateAs[OrderLine](ctx, orders, monger.NewPipeline().Unwind("items", false).Build())
+func AggregateAs[R any, T any](ctx context.Context, r *Repository[T], pipeline []M) (out []R, err error) {
+	ctx, span := r.startSpan(ctx, "AggregateAs")
+	defer func() { span.end(err, int64(len(out))) }()
+	return aggregateAs[R](ctx, r, pipeline)
+}
+
+// aggregateAs executa o pipeline e decodifica o resultado em R (o span fica com quem chama).
+func aggregateAs[R any, T any](ctx context.Context, r *Repository[T], pipeline []M) ([]R, error) {
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	cursor, err := r.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	return decodeCursor[R](ctx, cursor, r.coll.Name())
+}
+
+// Aggregate executa um pipeline de agregação na coleção do Repository e decodifica todos os
+// documentos resultantes em out (ponteiro para slice). É a forma não genérica de AggregateAs,
+// com as mesmas restrições (WithRowSecurity, WithSoftDelete); com WithAllowDiskUse, estágios
+// que passam do limite de memória do servidor (100MB) podem usar disco.
+//
+// Exemplo de uso:
+//
+//	var totals []struct {
+//	    Region string  `bson:"_id"`
+//	    Total  float64 `bson:"total"`
+//	}
+//	err := orders.Aggregate(ctx, []monger.M{
+//	    {"$match": monger.M{"status": "paid"}},
+//	    {"$group": monger.M{"_id": "$region", "total": monger.M{"$sum": "$amount"}}},
+//	}, &totals)
+func (r *Repository[T]) Aggregate(ctx context.Context, pipeline []M, out any) (err error) {
+	ctx, span := r.startSpan(ctx, "Aggregate")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	cursor, err := r.aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return decodeAll(ctx, cursor, out, r.coll.Name())
+}
+
+// FindBatched executa várias buscas em uma única ida ao servidor e retorna um slice de
+// resultados por filtro, na mesma ordem de filters.
+//
+// Os filtros são combinados com $or (etapa que pode usar índices) e os documentos são
+// redistribuídos no servidor com $facet, reaplicando cada filtro individualmente. Assim,
+// um documento que satisfaz mais de um filtro aparece em todos os slices correspondentes.
+// A projeção (opcional) é aplicada depois da redistribuição, então pode omitir campos
+// usados nos filtros.
+//
+// Observação: o resultado do $facet é um único documento e está sujeito ao limite de 16MB
+// do BSON; use FindBatched para lotes de buscas pequenas (ex.: resolvers GraphQL).
+//
+// Exemplo de uso:
+//
+//	res, err := users.FindBatched(ctx, []*monger.FilterBuilder{
+//	    monger.Filter().Eq("teamId", teamA),
+//	    monger.Filter().Eq("teamId", teamB),
+//	}, monger.Select("name"))
+//	// res[0]: usuários do time A; res[1]: usuários do time B
+func (r *Repository[T]) FindBatched(ctx context.Context, filters []*FilterBuilder, p *ProjectBuilder) (results [][]T, err error) {
+	ctx, span := r.startSpan(ctx, "FindBatched")
+	defer func() { span.end(err, batchedDocs(results)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if len(filters) == 0 {
+		return [][]T{}, nil
+	}
+
+	branches := make([]M, len(filters))
+	facets := M{}
+	for i, f := range filters {
+		branch := M{}
+		if f != nil {
+			branch = f.Build()
+		}
+		branches[i] = branch
+
+		stages := []M{{"$match": branch}}
+		if p != nil {
+			stages = append(stages, M{"$project": p.Build()})
+		}
+		facets[facetKey(i)] = stages
+	}
+
+	// r.aggregate insere antes do $or o $match com as restrições do repositório
+	pipeline := []M{
+		{"$match": M{"$or": branches}},
+		{"$facet": facets},
+	}
+
+	cursor, err := r.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var out map[string][]T
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&out); err != nil {
+			return nil, wrapDecodeError(r.coll.Name(), cursor.Current, err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	results = make([][]T, len(filters))
+	for i := range filters {
+		results[i] = out[facetKey(i)]
+		if results[i] == nil {
+			results[i] = []T{}
+		}
+		for j := range results[i] {
+			if err := afterFind(ctx, &results[i][j]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// batchedDocs conta os documentos de todos os slices de FindBatched (para o span).
+func batchedDocs[T any](results [][]T) int64 {
+	var n int64
+	for _, r := range results {
+		n += int64(len(r))
+	}
+	return n
+}
+
+// facetKey gera o nome do ramo do $facet para o índice informado.
+func facetKey(i int) string {
+	return "f" + strconv.Itoa(i)
+}
+
+// GroupCount conta os documentos do filtro (f nil: todos) agrupados pelo valor de field, com
+// um $group no servidor. Como em GroupCountWithTotal, as chaves do mapa são o valor do campo
+// convertido para string, e documentos sem o campo (ou com valor nulo) ficam na chave "".
+// Para chaves tipadas, use GroupCountBy.
+//
+// Exemplo de uso:
+//
+//	byStatus, err := orders.GroupCount(ctx, "status", monger.Filter().Gte("createdAt", since))
+//	// byStatus: {"paid": 120, "pending": 30}
+func (r *Repository[T]) GroupCount(ctx context.Context, field string, f *FilterBuilder) (map[string]int64, error) {
+	groups, err := groupCounts[any](ctx, r, "GroupCount", field, f)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(groups))
+	for _, g := range groups {
+		counts[groupKey(g.ID)] += g.Count
+	}
+	return counts, nil
+}
+
+// GroupCountBy é a forma tipada de GroupCount: cada chave do grupo é decodificada em K
+// (ex.: int para um campo numérico, bool, primitive.ObjectID). Documentos sem o campo (ou
+// com valor nulo) ficam na chave de valor zero de K; uma chave que não possa ser decodificada
+// em K retorna erro.
+//
+// Exemplo de uso:
+//
+//	byRating, err := monger.GroupCountBy[int](ctx, reviews, "rating", nil)
+//	// byRating: {1: 4, 4: 31, 5: 87}
+func GroupCountBy[K comparable, T any](ctx context.Context, r *Repository[T], field string, f *FilterBuilder) (map[K]int64, error) {
+	groups, err := groupCounts[K](ctx, r, "GroupCountBy", field, f)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[K]int64, len(groups))
+	for _, g := range groups {
+		counts[g.ID] += g.Count
+	}
+	return counts, nil
+}
+
+// groupCounts executa {$group: {_id: "$field", count: {$sum: 1}}} sobre o filtro e decodifica
+// cada grupo com a chave em K.
+func groupCounts[K any, T any](ctx context.Context, r *Repository[T], op, field string, f *FilterBuilder) (groups []groupCountOf[K], err error) {
+	ctx, span := r.startSpan(ctx, op)
+	defer func() { span.end(err, int64(len(groups))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if field == "" {
+		return nil, fmt.Errorf("field não pode ser vazio")
+	}
+	match := M{}
+	if f != nil {
+		match = f.Build()
+	}
+	pipeline := []M{
+		{"$match": match},
+		{"$group": M{"_id": fieldRef(field), "count": M{"$sum": 1}}},
+	}
+
+	cursor, err := r.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, wrapDecodeError(r.coll.Name(), nil, err)
+	}
+	return groups, nil
+}
+
+// GroupCountWithTotal conta os documentos agrupados pelo valor de field e, na mesma
+// agregação ($facet), o total geral do filtro. Como os dois números vêm do mesmo
+// snapshot e de uma única ida ao servidor, a soma dos grupos sempre bate com o total.
+//
+// As chaves do mapa são o valor do campo convertido para string; documentos sem o
+// campo (ou com valor nulo) são agrupados na chave "".
+//
+// Exemplo de uso:
+//
+//	byStatus, total, err := orders.GroupCountWithTotal(ctx, "status", monger.Filter().Gte("createdAt", since))
+//	// byStatus: {"paid": 120, "pending": 30}, total: 150
+func (r *Repository[T]) GroupCountWithTotal(ctx context.Context, field string, f *FilterBuilder) (groups map[string]int64, total int64, err error) {
+	ctx, span := r.startSpan(ctx, "GroupCountWithTotal")
+	defer func() { span.end(err, total) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if field == "" {
+		return nil, 0, fmt.Errorf("field não pode ser vazio")
+	}
+	match := M{}
+	if f != nil {
+		match = f.Build()
+	}
+
+	pipeline := []M{
+		{"$match": match},
+		{"$facet": M{
+			"groups": []M{{"$group": M{"_id": fieldRef(field), "count": M{"$sum": 1}}}},
+			"total":  []M{{"$count": "n"}},
+		}},
+	}
+
+	cursor, err := r.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var out struct {
+		Groups []groupCount `bson:"groups"`
+		Total  []struct {
+			N int64 `bson:"n"`
+		} `bson:"total"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&out); err != nil {
+			return nil, 0, wrapDecodeError(r.coll.Name(), cursor.Current, err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	groups = make(map[string]int64, len(out.Groups))
+	for _, g := range out.Groups {
+		groups[groupKey(g.ID)] += g.Count
+	}
+	if len(out.Total) > 0 {
+		total = out.Total[0].N
+	}
+	return groups, total, nil
+}
+
+// CountMany conta, em uma única agregação ($facet com um ramo $count por filtro), quantos
+// documentos satisfazem cada filtro. O resultado usa os mesmos rótulos do mapa filters; um
+// filtro nil conta todos os documentos.
+//
+// Como em FindBatched, os filtros são combinados com $or antes do $facet, para que a etapa
+// inicial possa usar índices.
+//
+// Exemplo de uso:
+//
+//	counts, err := customers.CountMany(ctx, map[string]*monger.FilterBuilder{
+//	    "active":  monger.Filter().Eq("status", "active"),
+//	    "pending": monger.Filter().Eq("status", "pending"),
+//	    "churned": monger.Filter().Eq("status", "churned"),
+//	})
+//	// counts["active"], counts["pending"], counts["churned"]
+func (r *Repository[T]) CountMany(ctx context.Context, filters map[string]*FilterBuilder) (counts map[string]int64, err error) {
+	ctx, span := r.startSpan(ctx, "CountMany")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	counts = make(map[string]int64, len(filters))
+	if len(filters) == 0 {
+		return counts, nil
+	}
+
+	// Rótulos do chamador podem conter "." ou "$": os ramos usam chaves geradas
+	labels := make([]string, 0, len(filters))
+	for label := range filters {
+		labels = append(labels, label)
+	}
+	branches := make([]M, len(labels))
+	facets := M{}
+	for i, label := range labels {
+		branch := M{}
+		if f := filters[label]; f != nil {
+			branch = f.Build()
+		}
+		branches[i] = branch
+		facets[facetKey(i)] = []M{{"$match": branch}, {"$count": "n"}}
+	}
+
+	// r.aggregate insere antes do $or o $match com as restrições do repositório
+	pipeline := []M{
+		{"$match": M{"$or": branches}},
+		{"$facet": facets},
+	}
+
+	cursor, err := r.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var out map[string][]struct {
+		N int64 `bson:"n"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&out); err != nil {
+			return nil, wrapDecodeError(r.coll.Name(), cursor.Current, err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, label := range labels {
+		counts[label] = 0
+		if branch := out[facetKey(i)]; len(branch) > 0 {
+			counts[label] = branch[0].N
+		}
+	}
+	return counts, nil
+}
+
+// Sum soma os valores numéricos de field nos documentos do filtro (f nil: todos), com um
+// $match + $group executado no servidor. Valores não numéricos ou ausentes são ignorados;
+// sem documentos, retorna 0.
+//
+// Exemplo de uso:
+//
+//	revenue, err := orders.Sum(ctx, "amount", monger.Filter().Eq("status", "paid"))
+func (r *Repository[T]) Sum(ctx context.Context, field string, f *FilterBuilder) (float64, error) {
+	v, err := r.accumulate(ctx, "Sum", "$sum", field, f)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	return v, err
+}
+
+// Avg calcula a média dos valores numéricos de field nos documentos do filtro (f nil: todos).
+// Valores não numéricos ou ausentes são ignorados; se não houver nenhum valor numérico, não
+// há média e retorna ErrNotFound.
+//
+// Exemplo de uso:
+//
+//	ticket, err := orders.Avg(ctx, "amount", monger.Filter().Eq("status", "paid"))
+func (r *Repository[T]) Avg(ctx context.Context, field string, f *FilterBuilder) (float64, error) {
+	return r.accumulate(ctx, "Avg", "$avg", field, f)
+}
+
+// Min retorna o menor valor de field nos documentos do filtro (f nil: todos). Sem documentos
+// com o campo, retorna ErrNotFound; se o menor valor não for numérico (ex.: texto ou data),
+// retorna erro.
+//
+// Exemplo de uso:
+//
+//	cheapest, err := products.Min(ctx, "price", monger.Filter().Eq("active", true))
+func (r *Repository[T]) Min(ctx context.Context, field string, f *FilterBuilder) (float64, error) {
+	return r.accumulate(ctx, "Min", "$min", field, f)
+}
+
+// Max retorna o maior valor de field nos documentos do filtro (f nil: todos), com as mesmas
+// regras de Min.
+//
+// Exemplo de uso:
+//
+//	highest, err := orders.Max(ctx, "amount", nil)
+func (r *Repository[T]) Max(ctx context.Context, field string, f *FilterBuilder) (float64, error) {
+	return r.accumulate(ctx, "Max", "$max", field, f)
+}
+
+// accumulate executa {$group: {_id: null, v: {<acc>: "$field"}}} sobre o filtro e retorna v
+// como float64. Sem resultado (ou resultado nulo), retorna ErrNotFound.
+func (r *Repository[T]) accumulate(ctx context.Context, op, acc, field string, f *FilterBuilder) (v float64, err error) {
+	ctx, span := r.startSpan(ctx, op)
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if field == "" {
+		return 0, fmt.Errorf("field não pode ser vazio")
+	}
+	match := M{}
+	if f != nil {
+		match = f.Build()
+	}
+	pipeline := []M{
+		{"$match": match},
+		{"$group": M{"_id": nil, "v": M{acc: fieldRef(field)}}},
+	}
+
+	cursor, err := r.aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var out struct {
+		V bson.RawValue `bson:"v"`
+	}
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return 0, err
+		}
+		return 0, ErrNotFound
+	}
+	if err := cursor.Decode(&out); err != nil {
+		return 0, err
+	}
+	if out.V.Type == 0 || out.V.Type == bson.TypeNull {
+		return 0, ErrNotFound
+	}
+	v, ok := numericValue(out.V)
+	if !ok {
+		return 0, fmt.Errorf("%s de %q: o resultado não é numérico (tipo %s)", op, field, out.V.Type)
+	}
+	return v, nil
+}
+
+// numericValue converte um valor BSON numérico (double, int32, int64, decimal128) para float64.
+func numericValue(rv bson.RawValue) (float64, bool) {
+	switch rv.Type {
+	case bson.TypeDouble:
+		return rv.Double(), true
+	case bson.TypeInt32, bson.TypeInt64:
+		return float64(rv.AsInt64()), true
+	case bson.TypeDecimal128:
+		f, err := strconv.ParseFloat(rv.Decimal128().String(), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// groupCount é o formato de cada grupo gerado por {$group: {_id: ..., count: {$sum: 1}}}.
+type groupCount = groupCountOf[any]
+
+// groupCountOf é o formato de um grupo com a chave decodificada em K.
+type groupCountOf[K any] struct {
+	ID    K     `bson:"_id"`
+	Count int64 `bson:"count"`
+}
+
+// groupKey converte a chave de um grupo para string (nulo/ausente vira "").
+func groupKey(v any) string {
+	switch k := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return k
+	case primitive.ObjectID:
+		return k.Hex()
+	default:
+		return fmt.Sprint(k)
+	}
+}
+
+// FindOrphans encontra documentos de child cujo childField referencia um _id que não existe
+// em parent (chave estrangeira "pendurada"). A verificação é feita no servidor com $lookup,
+// então funciona bem em coleções grandes; documentos sem o campo (ou com valor nulo) não
+// são considerados órfãos.
+//
+// As duas coleções precisam estar no mesmo banco (restrição do $lookup).
+//
+// Exemplo de uso:
+//
+//	// pedidos cujo customerId não existe em customers
+//	orphans, err := monger.FindOrphans(ctx, ordersRepo, "customerId", customersRepo)
+func FindOrphans[T any, U any](ctx context.Context, child *Repository[T], childField string, parent *Repository[U]) (out []T, err error) {
+	ctx, span := child.startSpan(ctx, "FindOrphans")
+	defer func() { span.end(err, int64(len(out))) }()
+	ctx, cancel := child.cfg.withTimeout(ctx)
+	defer cancel()
+	if childField == "" {
+		return nil, fmt.Errorf("childField não pode ser vazio")
+	}
+	if child.coll.Database().Name() != parent.coll.Database().Name() {
+		return nil, fmt.Errorf("child e parent precisam estar no mesmo banco")
+	}
+
+	const joined = "_mongerParent"
+	pipeline := []M{
+		{"$match": M{childField: M{"$exists": true, "$ne": nil}}},
+		{"$lookup": M{
+			"from":         parent.coll.Name(),
+			"localField":   childField,
+			"foreignField": "_id",
+			"as":           joined,
+		}},
+		{"$match": M{joined: M{"$size": 0}}},
+		{"$project": M{joined: 0}},
+	}
+	return aggregateAs[T](ctx, child, pipeline)
+}
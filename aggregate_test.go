@@ -0,0 +1,126 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: aggregate_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes do Pipeline e das agregações do repositório.
+*/
+package monger
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// secret simula um model com campo cifrado, decifrado no AfterFind.
+type secret struct {
+	ID    string `bson:"_id"`
+	Value string `bson:"value"`
+}
+
+func (s *secret) AfterFind(context.Context) error {
+	s.Value = strings.TrimPrefix(s.Value, "enc:")
+	return nil
+}
+
+func TestAggregateRunsAfterFind(t *testing.T) {
+	docs := []bson.D{
+		{{Key: "_id", Value: "a"}, {Key: "value", Value: "enc:1"}},
+		{{Key: "_id", Value: "b"}, {Key: "value", Value: "enc:2"}},
+	}
+	check := func(t *testing.T, got []string) {
+		t.Helper()
+		if len(got) != 2 || got[0] != "1" || got[1] != "2" {
+			t.Errorf("valores = %v, esperado [1 2] (AfterFind não rodou?)", got)
+		}
+	}
+
+	mockRepo(t, nil, func(t *testing.T, mt *mtest.T, r *Repository[secret]) {
+		mt.AddMockResponses(cursorReply(mt, docs...))
+		var out []secret
+		if err := r.Aggregate(context.Background(), []M{{"$match": M{}}}, &out); err != nil {
+			t.Fatal(err)
+		}
+		check(t, []string{out[0].Value, out[1].Value})
+
+		mt.AddMockResponses(cursorReply(mt, docs...))
+		var ptrs []*secret
+		if err := NewPipeline().Match(nil).Decode(context.Background(), r, &ptrs); err != nil {
+			t.Fatal(err)
+		}
+		check(t, []string{ptrs[0].Value, ptrs[1].Value})
+
+		mt.AddMockResponses(cursorReply(mt, docs...))
+		var raw []M
+		if err := r.Aggregate(context.Background(), nil, &raw); err != nil {
+			t.Fatal(err)
+		}
+		if raw[0]["value"] != "enc:1" {
+			t.Errorf("M sem hook foi alterado: %v", raw[0])
+		}
+	})
+}
+
+func TestPipelineUnwind(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		preserve bool
+		want     M
+	}{
+		{"sem preservar", "items", false, M{"$unwind": M{"path": "$items", "preserveNullAndEmptyArrays": false}}},
+		{"preservando nulos e vazios", "items", true, M{"$unwind": M{"path": "$items", "preserveNullAndEmptyArrays": true}}},
+		{"path já com $", "$items", false, M{"$unwind": M{"path": "$items", "preserveNullAndEmptyArrays": false}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPipeline().Unwind(tt.path, tt.preserve).Build()
+			if len(got) != 1 || !reflect.DeepEqual(got[0], tt.want) {
+				t.Errorf("pipeline = %#v\nesperado [%#v]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateRetryOnlyReads(t *testing.T) {
+	stepDown := bson.D{{Key: "ok", Value: 0}, {Key: "code", Value: 189}, {Key: "errmsg", Value: "primary stepped down"}}
+	ctx := context.Background()
+
+	mockRepo(t, []Option{WithRetry(3, time.Millisecond)}, func(t *testing.T, mt *mtest.T, r *Repository[secret]) {
+		// Leitura: o driver repete uma vez e o WithRetry, as demais
+		mt.AddMockResponses(stepDown, stepDown, cursorReply(mt))
+		var out []secret
+		if err := r.Aggregate(ctx, []M{{"$match": M{}}}, &out); err != nil {
+			t.Errorf("agregação de leitura não foi retentada: %v", err)
+		}
+
+		for _, last := range []M{{"$out": "archive"}, {"$merge": M{"into": "archive"}}} {
+			mt.ClearEvents()
+			mt.AddMockResponses(stepDown, cursorReply(mt))
+			if err := r.Aggregate(ctx, []M{{"$match": M{}}, last}, &out); err == nil {
+				t.Errorf("pipeline com %v foi retentado", last)
+			}
+			mt.ClearMockResponses()
+			if n := len(mt.GetAllStartedEvents()); n != 1 {
+				t.Errorf("pipeline com %v: %d comandos enviados, esperado 1", last, n)
+			}
+		}
+	})
+}
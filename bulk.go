@@ -0,0 +1,375 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: bulk.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define utilitários de escrita em lote (BulkWrite): o
+	BulkWriter, que monta o lote com as mesmas regras dos métodos do
+	repositório, e a retentativa apenas das operações que falharam por erros
+	transitórios.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkWriter acumula inserções, updates e exclusões para executá-los em uma única ida ao
+// servidor (BulkWrite). Cada operação segue as mesmas regras do método equivalente do
+// repositório (update parcial, timestamps, versão, campos imutáveis, soft-delete, hooks).
+//
+// As operações são montadas em Execute (com o ctx da execução, que os hooks recebem): se
+// alguma for inválida (ex.: ID inválido, patch sem campos), Execute retorna o erro sem
+// executar nada. Não é seguro para uso concorrente.
+//
+// Exemplo de uso:
+//
+//	res, err := products.Bulk().
+//	    Insert(&Product{SKU: "A1"}).
+//	    UpdateByID(id, &ProductPatch{Price: monger.Value(9.9)}).
+//	    DeleteByID(oldID).
+//	    Execute(ctx)
+type BulkWriter[T any] struct {
+	repo      *Repository[T]
+	ops       []bulkOp
+	unordered bool
+}
+
+// bulkOp monta uma operação do lote no momento da execução.
+type bulkOp func(ctx context.Context) (mongo.WriteModel, error)
+
+// BulkResult traz as contagens de um BulkWriter.Execute.
+type BulkResult struct {
+	InsertedCount int64 `json:"insertedCount"`
+	MatchedCount  int64 `json:"matchedCount"`
+	ModifiedCount int64 `json:"modifiedCount"` // com WithSoftDelete, inclui os documentos marcados como excluídos
+	DeletedCount  int64 `json:"deletedCount"`
+	UpsertedCount int64 `json:"upsertedCount"`
+
+	// UpsertedIDs mapeia a posição da operação no lote para o _id (hex) inserido por upsert.
+	UpsertedIDs map[int]string `json:"upsertedIds"`
+}
+
+// Bulk cria um BulkWriter vazio para o repositório. Por padrão o lote é ordenado (a primeira
+// falha interrompe as operações seguintes); use Unordered para executar todas.
+func (r *Repository[T]) Bulk() *BulkWriter[T] {
+	return &BulkWriter[T]{repo: r}
+}
+
+// Unordered executa o lote sem ordem (ordered: false): uma falha não impede as demais
+// operações, e o servidor pode aplicá-las em paralelo.
+func (w *BulkWriter[T]) Unordered() *BulkWriter[T] {
+	w.unordered = true
+	return w
+}
+
+// Len retorna quantas operações o lote tem.
+func (w *BulkWriter[T]) Len() int {
+	return len(w.ops)
+}
+
+// Insert acrescenta a inserção do model (com BeforeInsert e os campos de WithTimestamps).
+func (w *BulkWriter[T]) Insert(model *T) *BulkWriter[T] {
+	w.ops = append(w.ops, func(ctx context.Context) (mongo.WriteModel, error) {
+		if model == nil {
+			return nil, fmt.Errorf("model não pode ser nil")
+		}
+		doc, err := w.repo.insertDocument(ctx, model)
+		if err != nil {
+			return nil, err
+		}
+		return mongo.NewInsertOneModel().SetDocument(doc), nil
+	})
+	return w
+}
+
+// UpdateByID acrescenta um update parcial ($set, mesmas regras do Repository.UpdateByID).
+// Com a versão esperada no patch (WithVersioning), um documento em outra versão simplesmente
+// não é atualizado (MatchedCount menor).
+func (w *BulkWriter[T]) UpdateByID(id string, patch any) *BulkWriter[T] {
+	w.ops = append(w.ops, func(ctx context.Context) (mongo.WriteModel, error) {
+		oid, err := w.repo.cfg.parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		set, expected, err := w.repo.partialSet(ctx, patch)
+		if err != nil {
+			return nil, err
+		}
+		filter := M{"_id": oid}
+		if expected != nil {
+			filter[w.repo.cfg.versionField] = expected
+		}
+		return mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(w.repo.setUpdate(set)), nil
+	})
+	return w
+}
+
+// Upsert acrescenta um upsert com update parcial (mesmas regras do Repository.Upsert).
+func (w *BulkWriter[T]) Upsert(f *FilterBuilder, patch any) *BulkWriter[T] {
+	w.ops = append(w.ops, func(ctx context.Context) (mongo.WriteModel, error) {
+		if f == nil || len(f.Build()) == 0 {
+			return nil, fmt.Errorf("filtro é obrigatório para Upsert")
+		}
+		if patch == nil {
+			return nil, fmt.Errorf("update não pode ser nil")
+		}
+		if err := beforeUpdate(ctx, patch); err != nil {
+			return nil, err
+		}
+		doc, err := w.repo.cfg.buildPartialUpdate(patch)
+		if err != nil {
+			return nil, err
+		}
+		delete(doc, "_id")
+		set, onInsert := w.repo.splitImmutable(doc)
+		if len(set) == 0 && len(onInsert) == 0 {
+			return nil, fmt.Errorf("nenhum campo para atualizar")
+		}
+		update := w.repo.touchUpsert(set, onInsert)
+		return mongo.NewUpdateOneModel().SetFilter(f.Build()).SetUpdate(update).SetUpsert(true), nil
+	})
+	return w
+}
+
+// DeleteByID acrescenta a exclusão do documento. Com WithSoftDelete, o documento é marcado
+// como excluído; como as cascatas (OnSoftDelete, WithCascade) não rodam dentro do lote,
+// Execute recusa exclusões quando o repositório tem cascatas (use Repository.DeleteByID).
+func (w *BulkWriter[T]) DeleteByID(id string) *BulkWriter[T] {
+	w.ops = append(w.ops, func(ctx context.Context) (mongo.WriteModel, error) {
+		oid, err := w.repo.cfg.parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		r := w.repo
+		if r.cfg.softDeleteField == "" {
+			return mongo.NewDeleteOneModel().SetFilter(M{"_id": oid}), nil
+		}
+		if len(r.cfg.onSoftDelete) > 0 {
+			return nil, fmt.Errorf("BulkWriter não executa cascatas de soft-delete; use DeleteByID do repositório")
+		}
+		set := M{r.cfg.softDeleteField: time.Now()}
+		r.touch(set)
+		filter := andFilters(M{"_id": oid}, r.softDeleteFilter())
+		return mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(r.setUpdate(set)), nil
+	})
+	return w
+}
+
+// Execute monta as operações e envia o lote ao servidor. Com um lote vazio, não faz nada.
+// Em caso de falha de alguma operação, o resultado parcial é retornado junto com o erro do
+// driver (mongo.BulkWriteException, com os índices das operações que falharam); se alguma
+// falha for de índice único, o erro também satisfaz errors.Is(err, ErrDuplicateKey).
+func (w *BulkWriter[T]) Execute(ctx context.Context) (result *BulkResult, err error) {
+	ctx, span := w.repo.startSpan(ctx, "BulkWrite")
+	defer func() { span.end(err, bulkDocs(result)) }()
+	ctx, cancel := w.repo.cfg.withTimeout(ctx)
+	defer cancel()
+	result = &BulkResult{UpsertedIDs: map[int]string{}}
+	if len(w.ops) == 0 {
+		return result, nil
+	}
+	models := make([]mongo.WriteModel, len(w.ops))
+	for i, op := range w.ops {
+		model, err := op(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("operação %d do lote: %w", i, err)
+		}
+		models[i] = model
+	}
+	if err := w.repo.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	res, err := w.repo.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(!w.unordered))
+	if res != nil {
+		result.InsertedCount = res.InsertedCount
+		result.MatchedCount = res.MatchedCount
+		result.ModifiedCount = res.ModifiedCount
+		result.DeletedCount = res.DeletedCount
+		result.UpsertedCount = res.UpsertedCount
+		for idx, id := range res.UpsertedIDs {
+			result.UpsertedIDs[int(idx)] = w.repo.cfg.idString(id)
+		}
+	}
+	return result, writeError(err)
+}
+
+// BulkWriteFailure descreve uma operação do lote que falhou em definitivo.
+type BulkWriteFailure struct {
+	Index     int    `json:"index"`     // posição da operação no lote original
+	Code      int    `json:"code"`      // código de erro do servidor (ex.: 11000 para chave duplicada)
+	Message   string `json:"message"`   // mensagem do servidor
+	Retryable bool   `json:"retryable"` // true se o erro era transitório, mas as retentativas se esgotaram
+}
+
+// BulkRetryResult separa o resultado de um BulkWriteRetry por operação.
+// Os índices se referem à posição da operação no lote original.
+type BulkRetryResult struct {
+	Succeeded []int              `json:"succeeded"` // sucesso na primeira tentativa
+	Retried   []int              `json:"retried"`   // falharam com erro transitório e tiveram sucesso ao retentar
+	Failed    []BulkWriteFailure `json:"failed"`    // falharam em definitivo
+
+	InsertedCount int64 `json:"insertedCount"`
+	MatchedCount  int64 `json:"matchedCount"`
+	ModifiedCount int64 `json:"modifiedCount"`
+	DeletedCount  int64 `json:"deletedCount"`
+	UpsertedCount int64 `json:"upsertedCount"`
+}
+
+// BulkWriteRetry executa as operações em lote (não ordenado) e, se algumas falharem com
+// erros transitórios (ex.: WriteConflict, primário em troca), reexecuta apenas essas,
+// até retries vezes. Erros permanentes (ex.: chave duplicada, validação) não são retentados.
+//
+// Entre as tentativas, espera um backoff exponencial com jitter: o de WithRetry ou, sem ele,
+// a partir de 100ms. Se o ctx terminar durante a espera, as operações pendentes entram em
+// result.Failed (com a última falha) e o erro do ctx é retornado.
+//
+// O lote é sempre executado sem ordem (ordered: false), para que uma falha não impeça as
+// operações seguintes. Se alguma operação falhar em definitivo, o resultado é retornado
+// junto com um erro que satisfaz errors.Is(err, ErrPartialWrite); consulte result.Failed.
+// Erros que não são por operação (ex.: rede, write concern) são retornados diretamente,
+// pois não é possível saber quais operações foram aplicadas.
+//
+// Exemplo de uso:
+//
+//	res, err := users.BulkWriteRetry(ctx, []mongo.WriteModel{
+//	    mongo.NewInsertOneModel().SetDocument(u1),
+//	    mongo.NewUpdateOneModel().SetFilter(monger.M{"_id": id}).SetUpdate(monger.M{"$set": patch}),
+//	}, 3)
+//	if errors.Is(err, monger.ErrPartialWrite) {
+//	    for _, f := range res.Failed { log.Println(f.Index, f.Message) }
+//	}
+func (r *Repository[T]) BulkWriteRetry(ctx context.Context, models []mongo.WriteModel, retries int) (result *BulkRetryResult, err error) {
+	ctx, span := r.startSpan(ctx, "BulkWriteRetry")
+	defer func() { span.end(err, retryDocs(result)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	result = &BulkRetryResult{Succeeded: []int{}, Retried: []int{}, Failed: []BulkWriteFailure{}}
+	if len(models) == 0 {
+		return result, nil
+	}
+	if err := r.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	pending := make([]int, len(models))
+	for i := range models {
+		pending[i] = i
+	}
+	opts := options.BulkWrite().SetOrdered(false)
+	backoff := r.cfg.retryBackoff
+	if backoff <= 0 {
+		backoff = bulkRetryBackoff
+	}
+	deferred := map[int]BulkWriteFailure{} // última falha transitória de cada operação pendente
+
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			// espera antes de retentar (ex.: eleição de um novo primário); com o ctx encerrado,
+			// as operações pendentes ficam com a última falha transitória
+			if err := sleepBackoff(ctx, backoff, attempt); err != nil {
+				for _, idx := range pending {
+					result.Failed = append(result.Failed, deferred[idx])
+				}
+				return result, err
+			}
+		}
+		batch := make([]mongo.WriteModel, len(pending))
+		for i, idx := range pending {
+			batch[i] = models[idx]
+		}
+
+		res, err := r.coll.BulkWrite(ctx, batch, opts)
+		if res != nil {
+			result.InsertedCount += res.InsertedCount
+			result.MatchedCount += res.MatchedCount
+			result.ModifiedCount += res.ModifiedCount
+			result.DeletedCount += res.DeletedCount
+			result.UpsertedCount += res.UpsertedCount
+		}
+
+		var bwe mongo.BulkWriteException
+		if err != nil && (!errors.As(err, &bwe) || bwe.WriteConcernError != nil) {
+			return result, err
+		}
+
+		failed := map[int]bool{}
+		next := []int{}
+		for _, we := range bwe.WriteErrors {
+			idx := pending[we.Index]
+			failed[we.Index] = true
+			retryable := isTransientWriteCode(we.Code)
+			failure := BulkWriteFailure{
+				Index:     idx,
+				Code:      we.Code,
+				Message:   we.Message,
+				Retryable: retryable,
+			}
+			if retryable && attempt < retries {
+				next = append(next, idx)
+				deferred[idx] = failure
+				continue
+			}
+			result.Failed = append(result.Failed, failure)
+		}
+		for i, idx := range pending {
+			if failed[i] {
+				continue
+			}
+			if attempt == 0 {
+				result.Succeeded = append(result.Succeeded, idx)
+			} else {
+				result.Retried = append(result.Retried, idx)
+			}
+		}
+		pending = next
+	}
+
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("%w: %d de %d operações falharam", ErrPartialWrite, len(result.Failed), len(models))
+	}
+	return result, nil
+}
+
+// bulkRetryBackoff é o backoff inicial de BulkWriteRetry sem WithRetry.
+const bulkRetryBackoff = 100 * time.Millisecond
+
+// isTransientWriteCode indica se um código de erro de escrita é transitório
+// (a mesma operação pode ter sucesso se reexecutada).
+func isTransientWriteCode(code int) bool {
+	switch code {
+	case 6, // HostUnreachable
+		7,     // HostNotFound
+		89,    // NetworkTimeout
+		91,    // ShutdownInProgress
+		112,   // WriteConflict
+		189,   // PrimarySteppedDown
+		262,   // ExceededTimeLimit
+		9001,  // SocketException
+		10107, // NotWritablePrimary
+		11600, // InterruptedAtShutdown
+		11602, // InterruptedDueToReplStateChange
+		13435, // NotPrimaryNoSecondaryOk
+		13436: // NotPrimaryOrSecondary
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: bulk_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes das escritas em lote (BulkWriter e BulkWriteRetry), sobre um servidor
+	simulado.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// writeErrorReply simula um lote em que a operação index falhou com o código informado.
+func writeErrorReply(index, code int) bson.D {
+	return okReply(
+		bson.E{Key: "n", Value: 0},
+		bson.E{Key: "writeErrors", Value: bson.A{bson.D{
+			{Key: "index", Value: index},
+			{Key: "code", Value: code},
+			{Key: "errmsg", Value: "falha simulada"},
+		}}},
+	)
+}
+
+func TestBulkWriteRetryBackoff(t *testing.T) {
+	type item struct {
+		ID string `bson:"_id"`
+	}
+	models := []mongo.WriteModel{mongo.NewInsertOneModel().SetDocument(item{ID: "a"})}
+	const backoff = 20 * time.Millisecond
+
+	mockRepo(t, []Option{WithRetry(1, backoff)}, func(t *testing.T, mt *mtest.T, r *Repository[item]) {
+		mt.AddMockResponses(writeErrorReply(0, 189), okReply(bson.E{Key: "n", Value: 1}))
+		start := time.Now()
+		res, err := r.BulkWriteRetry(context.Background(), models, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Retried) != 1 {
+			t.Errorf("Retried = %v, esperado [0]", res.Retried)
+		}
+		if elapsed := time.Since(start); elapsed < backoff/2 {
+			t.Errorf("retentou após %v, esperado ao menos %v de espera", elapsed, backoff/2)
+		}
+
+		// ctx encerrado durante a espera: a operação pendente fica em Failed
+		mt.AddMockResponses(writeErrorReply(0, 189))
+		ctx, cancel := context.WithTimeout(context.Background(), backoff/10)
+		defer cancel()
+		res, err = r.BulkWriteRetry(ctx, models, 3)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, esperado context.DeadlineExceeded", err)
+		}
+		if len(res.Failed) != 1 || res.Failed[0].Code != 189 || !res.Failed[0].Retryable {
+			t.Errorf("Failed = %+v, esperado a falha transitória da operação 0", res.Failed)
+		}
+	})
+}
+
+func TestBulkExecuteDuplicateKey(t *testing.T) {
+	type item struct {
+		ID string `bson:"_id"`
+	}
+	mockRepo(t, nil, func(t *testing.T, mt *mtest.T, r *Repository[item]) {
+		reply := writeErrorReply(1, 11000)
+		reply[1].Value = 1 // n: a primeira inserção foi aplicada
+		mt.AddMockResponses(reply)
+		res, err := r.Bulk().Unordered().Insert(&item{ID: "a"}).Insert(&item{ID: "a"}).Execute(context.Background())
+		if !errors.Is(err, ErrDuplicateKey) {
+			t.Fatalf("err = %v, esperado ErrDuplicateKey", err)
+		}
+		var bwe mongo.BulkWriteException
+		if !errors.As(err, &bwe) || len(bwe.WriteErrors) != 1 || bwe.WriteErrors[0].Index != 1 {
+			t.Errorf("o erro do driver deveria continuar na cadeia: %v", err)
+		}
+		if res == nil || res.InsertedCount != 1 {
+			t.Errorf("resultado parcial = %+v, esperado InsertedCount 1", res)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: context.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define as chaves de contexto padronizadas do pacote, para que
+	metadados da requisição (ex.: quem executou a operação) cheguem de forma
+	consistente a hooks e auditorias.
+*/
+package monger
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// actorKey é a chave não exportada do ator no context.Context (evita colisões com outros pacotes).
+type actorKey struct{}
+
+// WithActor retorna um contexto que carrega o identificador de quem executa a operação
+// (ex.: id do usuário autenticado). O Repository repassa o mesmo ctx a todas as etapas
+// da operação, então hooks de auditoria podem recuperá-lo com ActorFromContext.
+//
+// Exemplo de uso:
+//
+//	ctx = monger.WithActor(ctx, currentUser.ID)
+//	_, err := users.UpdateByID(ctx, id, patch)
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+// ActorFromContext retorna o ator registrado com WithActor e se ele estava presente.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok
+}
+
+// includeDeletedKey marca no context.Context as leituras que devem incluir documentos excluídos.
+type includeDeletedKey struct{}
+
+// IncludeDeleted retorna um contexto em que as leituras do repositório (Find, FindAll,
+// FindPaged, Count, agregações, ...) incluem também os documentos marcados como excluídos
+// por WithSoftDelete. É o escape hatch para telas administrativas e auditorias; as
+// restrições de WithRowSecurity continuam valendo.
+//
+// Exemplo de uso:
+//
+//	all, err := users.FindAll(monger.IncludeDeleted(ctx), nil, nil, 0)
+func IncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// includesDeleted indica se o contexto foi marcado com IncludeDeleted.
+func includesDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return include
+}
+
+// collationKey guarda no context.Context a collation de UseCollation.
+type collationKey struct{}
+
+// UseCollation retorna um contexto em que as consultas do repositório (Find, FindOne,
+// FindAll, FindAs, FindOneAs, FindPaged, FindPage, FindPageHasMore, Count, Exists) usam a
+// collation informada, no lugar da configurada com WithCollation. Uma collation nil desliga a do repositório.
+//
+// Exemplo de uso:
+//
+//	ptBR := &options.Collation{Locale: "pt", Strength: 1}
+//	res, err := users.FindPaged(monger.UseCollation(ctx, ptBR), nil, nil, 0, 20, monger.Sort().Asc("name").Build())
+func UseCollation(ctx context.Context, c *options.Collation) context.Context {
+	return context.WithValue(ctx, collationKey{}, c)
+}
+
+// collationFor retorna a collation das consultas: a de UseCollation, se o ctx tiver, ou a de
+// WithCollation (nil quando nenhuma foi definida).
+func (c *config) collationFor(ctx context.Context) *options.Collation {
+	if coll, ok := ctx.Value(collationKey{}).(*options.Collation); ok {
+		return coll
+	}
+	return c.collation
+}
+
+// withTimeout aplica ao ctx o prazo padrão de WithTimeout (se configurado). Um prazo já
+// existente menor prevalece; o cancel retornado deve sempre ser chamado.
+func (c *config) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= c.timeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
@@ -0,0 +1,170 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: decode.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define a decodificação de resultados do driver com erros
+	contextualizados (coleção, campo e _id do documento que falhou).
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DecodeError indica que um documento retornado pelo servidor não pôde ser decodificado
+// no tipo de destino (ex.: o tipo de um campo mudou no banco). Use errors.As para obter
+// os detalhes; o erro original do driver continua acessível via errors.Unwrap.
+type DecodeError struct {
+	Collection string // coleção consultada
+	Field      string // caminho pontuado do campo que falhou (vazio se o driver não informar)
+	DocumentID any    // _id do documento (nil se indisponível)
+	Err        error  // erro original do driver
+}
+
+func (e *DecodeError) Error() string {
+	msg := e.Err.Error()
+	var de *bsoncodec.DecodeError
+	if errors.As(e.Err, &de) {
+		msg = de.Unwrap().Error()
+	}
+
+	var b strings.Builder
+	b.WriteString("erro ao decodificar")
+	if e.Field != "" {
+		fmt.Fprintf(&b, " o campo %q", e.Field)
+	}
+	if e.DocumentID != nil {
+		fmt.Fprintf(&b, " do documento %v", e.DocumentID)
+	}
+	fmt.Fprintf(&b, " na coleção %s: %s", e.Collection, msg)
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// wrapDecodeError enriquece um erro de decodificação com a coleção, o campo e o _id do documento.
+func wrapDecodeError(collection string, raw bson.Raw, err error) error {
+	de := &DecodeError{Collection: collection, Err: err}
+
+	var codecErr *bsoncodec.DecodeError
+	if errors.As(err, &codecErr) {
+		de.Field = strings.Join(codecErr.Keys(), ".")
+	}
+	if raw != nil {
+		if idVal, lookupErr := raw.LookupErr("_id"); lookupErr == nil {
+			var id any
+			if idVal.Unmarshal(&id) == nil {
+				if oid, ok := id.(primitive.ObjectID); ok {
+					id = oid.Hex()
+				}
+				de.DocumentID = id
+			}
+		}
+	}
+	return de
+}
+
+// decodeCursor percorre o cursor decodificando cada documento em R.
+// Diferente de cursor.All, um erro de decodificação identifica o documento que falhou.
+// Sem resultados, retorna um slice vazio (nunca nil).
+func decodeCursor[R any](ctx context.Context, cursor *mongo.Cursor, collection string) ([]R, error) {
+	results := []R{}
+	for cursor.Next(ctx) {
+		var item R
+		if err := cursor.Decode(&item); err != nil {
+			return nil, wrapDecodeError(collection, cursor.Current, err)
+		}
+		if err := afterFind(ctx, &item); err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// decodeSingle decodifica o resultado de um FindOne/FindOneAnd* em R (e chama AfterFind).
+// Erros da operação (inclusive mongo.ErrNoDocuments) são retornados sem alteração.
+func decodeSingle[R any](ctx context.Context, res *mongo.SingleResult, collection string) (*R, error) {
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+	var out R
+	if err := res.Decode(&out); err != nil {
+		if res.Err() != nil {
+			return nil, err
+		}
+		raw, _ := res.Raw()
+		return nil, wrapDecodeError(collection, raw, err)
+	}
+	if err := afterFind(ctx, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// afterFinderType é o tipo da interface AfterFinder, para checagem por reflexão.
+var afterFinderType = reflect.TypeOf((*AfterFinder)(nil)).Elem()
+
+// decodeAll decodifica todos os documentos do cursor em out (ponteiro para slice), como
+// cursor.All. Se o tipo dos elementos implementar AfterFinder (em T ou *T), cada documento é
+// decodificado individualmente e o hook é chamado, como em decodeCursor.
+func decodeAll(ctx context.Context, cursor *mongo.Cursor, out any, collection string) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("out deve ser um ponteiro para slice, recebido %T", out)
+	}
+	slice := rv.Elem()
+	elem := slice.Type().Elem()
+	hooked := elem.Implements(afterFinderType) || reflect.PointerTo(elem).Implements(afterFinderType)
+	if !hooked {
+		if err := cursor.All(ctx, out); err != nil {
+			return wrapDecodeError(collection, nil, err)
+		}
+		return nil
+	}
+
+	results := reflect.MakeSlice(slice.Type(), 0, 0)
+	for cursor.Next(ctx) {
+		item := reflect.New(elem)
+		if err := cursor.Decode(item.Interface()); err != nil {
+			return wrapDecodeError(collection, cursor.Current, err)
+		}
+		target := item.Interface()
+		if elem.Kind() == reflect.Pointer {
+			target = item.Elem().Interface()
+		}
+		if err := afterFind(ctx, target); err != nil {
+			return err
+		}
+		results = reflect.Append(results, item.Elem())
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	slice.Set(results)
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: errors.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define os erros sentinela do pacote, para que o chamador
+	possa usar errors.Is sem depender de detalhes internos do driver.
+*/
+package monger
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrNotFound indica que nenhum documento satisfez o filtro da operação.
+var ErrNotFound = errors.New("documento não encontrado")
+
+// ErrLeaseLost indica que o worker não é mais dono do lease do documento
+// (o lease expirou, foi liberado ou foi reservado por outro worker).
+var ErrLeaseLost = errors.New("lease perdido: documento não pertence mais ao worker")
+
+// ErrPartialWrite indica que parte das operações de um lote falhou em definitivo;
+// o resultado retornado junto detalha quais.
+var ErrPartialWrite = errors.New("escrita em lote parcialmente falha")
+
+// ErrVersionConflict indica que o documento foi alterado por outra escrita desde que foi
+// lido (a versão esperada não confere), no controle de concorrência otimista.
+var ErrVersionConflict = errors.New("conflito de versão: documento alterado por outra escrita")
+
+// ErrImmutableField indica uma tentativa de alterar um campo imutável (WithImmutableFields ou
+// tag `monger:"immutable"`) com WithRejectImmutable habilitado.
+var ErrImmutableField = errors.New("campo imutável não pode ser alterado")
+
+// ErrDuplicateKey indica que a escrita violou um índice único (ex.: _id já existente).
+var ErrDuplicateKey = errors.New("chave duplicada")
+
+// ErrStopIteration pode ser retornado pelo callback de Iterate para encerrar a iteração
+// antes do fim sem que isso seja tratado como erro.
+var ErrStopIteration = errors.New("iteração interrompida")
+
+// ErrInvalidID indica um id que não pode ser convertido no _id da coleção (por padrão, que
+// não é o hex de um ObjectID; veja WithIDCodec).
+var ErrInvalidID = errors.New("id inválido")
+
+// notFound converte mongo.ErrNoDocuments em ErrNotFound. O erro do driver continua na cadeia,
+// então errors.Is(err, mongo.ErrNoDocuments) também continua funcionando.
+func notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}
+
+// writeError converte violações de índice único (código 11000) em ErrDuplicateKey,
+// mantendo o erro do driver na cadeia.
+func writeError(err error) error {
+	if err != nil && mongo.IsDuplicateKeyError(err) && !errors.Is(err, ErrDuplicateKey) {
+		return fmt.Errorf("%w: %w", ErrDuplicateKey, err)
+	}
+	return err
+}
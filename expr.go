@@ -0,0 +1,164 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: expr.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o suporte a $expr no FilterBuilder e os construtores
+	de expressões de agregação usados dentro dele (ex.: aritmética de datas).
+*/
+package monger
+
+import "time"
+
+// Expr adiciona uma expressão de agregação ao filtro: {$expr: expr}.
+// Se o filtro já tiver um $expr, as duas expressões são combinadas com $and.
+//
+// Exemplo de uso:
+//
+//	f := monger.Filter().Expr(monger.M{"$gt": []any{"$spent", "$budget"}})
+func (b *FilterBuilder) Expr(expr M) *FilterBuilder {
+	if prev, ok := b.f["$expr"]; ok {
+		b.f["$expr"] = M{"$and": []any{prev, expr}}
+		return b
+	}
+	b.f["$expr"] = expr
+	return b
+}
+
+// ExprDateDiffLt filtra documentos em que fieldA - fieldB (dois campos de data) é menor que d.
+//
+// Exemplo de uso:
+//
+//	// expiresAt cai em até 7 dias após createdAt
+//	f := monger.Filter().ExprDateDiffLt("expiresAt", "createdAt", 7*24*time.Hour)
+func (b *FilterBuilder) ExprDateDiffLt(fieldA, fieldB string, d time.Duration) *FilterBuilder {
+	return b.Expr(M{"$lt": []any{DateDiffExpr(fieldA, fieldB), d.Milliseconds()}})
+}
+
+// ExprDateDiffGt filtra documentos em que fieldA - fieldB (dois campos de data) é maior que d.
+//
+// Exemplo de uso:
+//
+//	// tickets resolvidos mais de 48h após a abertura (SLA estourado)
+//	f := monger.Filter().ExprDateDiffGt("resolvedAt", "openedAt", 48*time.Hour)
+func (b *FilterBuilder) ExprDateDiffGt(fieldA, fieldB string, d time.Duration) *FilterBuilder {
+	return b.Expr(M{"$gt": []any{DateDiffExpr(fieldA, fieldB), d.Milliseconds()}})
+}
+
+// --- Comparações entre campos do mesmo documento ---
+// O prefixo "$" é opcional nos nomes dos campos ("used" e "$used" são equivalentes).
+
+// FieldEq filtra documentos em que fieldA == fieldB: {$expr: {$eq: ["$a", "$b"]}}.
+func (b *FilterBuilder) FieldEq(fieldA, fieldB string) *FilterBuilder {
+	return b.fieldCompare("$eq", fieldA, fieldB)
+}
+
+// FieldGt filtra documentos em que fieldA > fieldB.
+func (b *FilterBuilder) FieldGt(fieldA, fieldB string) *FilterBuilder {
+	return b.fieldCompare("$gt", fieldA, fieldB)
+}
+
+// FieldGte filtra documentos em que fieldA >= fieldB.
+//
+// Exemplo de uso:
+//
+//	// contas que atingiram a cota
+//	f := monger.Filter().FieldGte("used", "quota")
+func (b *FilterBuilder) FieldGte(fieldA, fieldB string) *FilterBuilder {
+	return b.fieldCompare("$gte", fieldA, fieldB)
+}
+
+// FieldLt filtra documentos em que fieldA < fieldB.
+func (b *FilterBuilder) FieldLt(fieldA, fieldB string) *FilterBuilder {
+	return b.fieldCompare("$lt", fieldA, fieldB)
+}
+
+// FieldLte filtra documentos em que fieldA <= fieldB.
+func (b *FilterBuilder) FieldLte(fieldA, fieldB string) *FilterBuilder {
+	return b.fieldCompare("$lte", fieldA, fieldB)
+}
+
+// FieldNe filtra documentos em que fieldA != fieldB.
+func (b *FilterBuilder) FieldNe(fieldA, fieldB string) *FilterBuilder {
+	return b.fieldCompare("$ne", fieldA, fieldB)
+}
+
+// ExprEq cria um filtro só com {$expr: {$eq: ["$a", "$b"]}}. Os construtores Expr* equivalem
+// aos métodos Field*, mas como filtros independentes podem ser combinados com Or, And e Nor.
+//
+// Exemplo de uso:
+//
+//	// estourou o orçamento ou o prazo
+//	f := monger.Filter().Eq("status", "active").
+//	    Or(monger.ExprGt("spent", "budget"), monger.ExprGt("finishedAt", "deadline"))
+func ExprEq(fieldA, fieldB string) *FilterBuilder { return Filter().FieldEq(fieldA, fieldB) }
+
+// ExprNe cria um filtro só com {$expr: {$ne: ["$a", "$b"]}}.
+func ExprNe(fieldA, fieldB string) *FilterBuilder { return Filter().FieldNe(fieldA, fieldB) }
+
+// ExprGt cria um filtro só com {$expr: {$gt: ["$a", "$b"]}}.
+func ExprGt(fieldA, fieldB string) *FilterBuilder { return Filter().FieldGt(fieldA, fieldB) }
+
+// ExprGte cria um filtro só com {$expr: {$gte: ["$a", "$b"]}}.
+func ExprGte(fieldA, fieldB string) *FilterBuilder { return Filter().FieldGte(fieldA, fieldB) }
+
+// ExprLt cria um filtro só com {$expr: {$lt: ["$a", "$b"]}}.
+func ExprLt(fieldA, fieldB string) *FilterBuilder { return Filter().FieldLt(fieldA, fieldB) }
+
+// ExprLte cria um filtro só com {$expr: {$lte: ["$a", "$b"]}}.
+func ExprLte(fieldA, fieldB string) *FilterBuilder { return Filter().FieldLte(fieldA, fieldB) }
+
+// fieldCompare adiciona {$expr: {op: ["$fieldA", "$fieldB"]}} ao filtro.
+// Atenção: em $expr, campo ausente é tratado como null (menor que qualquer número).
+func (b *FilterBuilder) fieldCompare(op, fieldA, fieldB string) *FilterBuilder {
+	return b.Expr(M{op: []any{fieldRef(fieldA), fieldRef(fieldB)}})
+}
+
+// ExprInField filtra documentos em que o valor de valueField está contido no array arrayField
+// do próprio documento: {$expr: {$in: ["$valueField", "$arrayField"]}}.
+//
+// Como o $in de agregação falha quando o segundo argumento não é um array, documentos em que
+// arrayField está ausente ou não é array simplesmente não correspondem ($isArray antes do $in).
+//
+// Exemplo de uso:
+//
+//	// tarefas cujo responsável também está entre os observadores
+//	f := monger.Filter().ExprInField("assigneeId", "watchers")
+func (b *FilterBuilder) ExprInField(valueField, arrayField string) *FilterBuilder {
+	return b.Expr(M{"$and": []any{
+		M{"$isArray": fieldRef(arrayField)},
+		M{"$in": []any{fieldRef(valueField), fieldRef(arrayField)}},
+	}})
+}
+
+// DateDiffExpr retorna a expressão fieldA - fieldB para dois campos de data.
+// O resultado da expressão é a diferença em milissegundos.
+func DateDiffExpr(fieldA, fieldB string) M {
+	return M{"$subtract": []any{fieldRef(fieldA), fieldRef(fieldB)}}
+}
+
+// DateAddExpr retorna a expressão field + d para um campo de data, usando $dateAdd
+// (requer MongoDB 5.0+). O resultado é uma data, útil para comparar com outro campo.
+//
+// Exemplo de uso:
+//
+//	// expiresAt antes de createdAt + 30 dias
+//	f := monger.Filter().Expr(monger.M{"$lt": []any{"$expiresAt", monger.DateAddExpr("createdAt", 30*24*time.Hour)}})
+func DateAddExpr(field string, d time.Duration) M {
+	return M{"$dateAdd": M{
+		"startDate": fieldRef(field),
+		"unit":      "millisecond",
+		"amount":    d.Milliseconds(),
+	}}
+}
@@ -0,0 +1,98 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: expr_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes dos filtros com $expr (comparações entre campos e expressões).
+*/
+package monger
+
+import "testing"
+
+func TestFieldCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		f    *FilterBuilder
+		want M
+	}{
+		{"FieldEq", Filter().FieldEq("a", "b"), M{"$expr": M{"$eq": []any{"$a", "$b"}}}},
+		{"FieldNe", Filter().FieldNe("a", "b"), M{"$expr": M{"$ne": []any{"$a", "$b"}}}},
+		{"FieldGt", Filter().FieldGt("used", "quota"), M{"$expr": M{"$gt": []any{"$used", "$quota"}}}},
+		{"FieldGte", Filter().FieldGte("used", "quota"), M{"$expr": M{"$gte": []any{"$used", "$quota"}}}},
+		{"FieldLt", Filter().FieldLt("used", "quota"), M{"$expr": M{"$lt": []any{"$used", "$quota"}}}},
+		{"FieldLte", Filter().FieldLte("used", "quota"), M{"$expr": M{"$lte": []any{"$used", "$quota"}}}},
+		{"prefixo $ opcional", Filter().FieldGt("$used", "$quota"), M{"$expr": M{"$gt": []any{"$used", "$quota"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFilter(t, tt.f.Build(), tt.want)
+		})
+	}
+}
+
+func TestExprFieldToField(t *testing.T) {
+	tests := []struct {
+		name string
+		f    *FilterBuilder
+		want M
+	}{
+		{"ExprGt", ExprGt("spent", "budget"), M{"$expr": M{"$gt": []any{"$spent", "$budget"}}}},
+		{"ExprLte", ExprLte("spent", "budget"), M{"$expr": M{"$lte": []any{"$spent", "$budget"}}}},
+		{"Expr", Filter().Expr(M{"$gt": []any{"$spent", "$budget"}}), M{"$expr": M{"$gt": []any{"$spent", "$budget"}}}},
+		{
+			"dois $expr combinados com $and",
+			Filter().FieldGt("spent", "budget").FieldLt("budget", "limit"),
+			M{"$expr": M{"$and": []any{
+				M{"$gt": []any{"$spent", "$budget"}},
+				M{"$lt": []any{"$budget", "$limit"}},
+			}}},
+		},
+		{
+			"em Or com outro filtro",
+			Filter().Or(ExprGt("spent", "budget"), Filter().Eq("status", "blocked")),
+			M{"$or": []M{
+				{"$expr": M{"$gt": []any{"$spent", "$budget"}}},
+				{"status": "blocked"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFilter(t, tt.f.Build(), tt.want)
+		})
+	}
+}
+
+func TestExprWithComparators(t *testing.T) {
+	f := Filter().
+		Eq("status", "active").
+		Gte("createdAt", 10).
+		FieldGt("spent", "budget").
+		Lt("createdAt", 20)
+
+	assertFilter(t, f.Build(), M{
+		"status":    "active",
+		"createdAt": M{"$gte": 10, "$lt": 20},
+		"$expr":     M{"$gt": []any{"$spent", "$budget"}},
+	})
+}
+
+func TestExprInField(t *testing.T) {
+	assertFilter(t, Filter().ExprInField("assigneeId", "watchers").Build(), M{
+		"$expr": M{"$and": []any{
+			M{"$isArray": "$watchers"},
+			M{"$in": []any{"$assigneeId", "$watchers"}},
+		}},
+	})
+}
@@ -0,0 +1,65 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: geo.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define os filtros geoespaciais ($near, $geoWithin) sobre
+	campos GeoJSON.
+
+	Atenção à ordem das coordenadas: GeoJSON usa [longitude, latitude],
+	o contrário da notação usual "lat, lng".
+*/
+package monger
+
+// GeoPoint retorna um ponto GeoJSON: {type: "Point", coordinates: [lng, lat]}.
+// Atenção: longitude primeiro (GeoJSON), ao contrário da notação usual "lat, lng".
+func GeoPoint(lng, lat float64) M {
+	return M{"type": "Point", "coordinates": []float64{lng, lat}}
+}
+
+// Near filtra documentos cujo ponto GeoJSON em field está a até maxMeters metros de
+// (lng, lat), ordenados do mais próximo ao mais distante. Com maxMeters <= 0 não há
+// distância máxima. Requer um índice 2dsphere em field.
+//
+// Atenção: longitude primeiro. $near também não pode ser usado em contagens (Count,
+// FindPaged); para contar, use GeoWithin com um círculo $centerSphere.
+//
+// Exemplo de uso:
+//
+//	// locais a até 2km de São Paulo (lng -46.63, lat -23.55)
+//	f := monger.Filter().Near("location", -46.63, -23.55, 2000)
+//	venues, err := repo.FindAll(ctx, f, nil, 20)
+func (b *FilterBuilder) Near(field string, lng, lat float64, maxMeters float64) *FilterBuilder {
+	near := M{"$geometry": GeoPoint(lng, lat)}
+	if maxMeters > 0 {
+		near["$maxDistance"] = maxMeters
+	}
+	b.mergeOp(field, "$near", near)
+	return b
+}
+
+// GeoWithin filtra documentos cuja geometria em field está inteiramente dentro de geometry
+// (um documento GeoJSON, ex.: Polygon ou MultiPolygon): {field: {$geoWithin: {$geometry: geometry}}}.
+// Diferente de Near, não ordena e pode ser usado em contagens.
+//
+// Exemplo de uso:
+//
+//	area := monger.M{"type": "Polygon", "coordinates": [][][]float64{{
+//	    {-46.70, -23.60}, {-46.60, -23.60}, {-46.60, -23.50}, {-46.70, -23.50}, {-46.70, -23.60},
+//	}}}
+//	f := monger.Filter().GeoWithin("location", area)
+func (b *FilterBuilder) GeoWithin(field string, geometry any) *FilterBuilder {
+	b.mergeOp(field, "$geoWithin", M{"$geometry": geometry})
+	return b
+}
@@ -0,0 +1,79 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: geo_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes dos filtros geoespaciais ($near e $geoWithin).
+*/
+package monger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNearCoordinateOrder(t *testing.T) {
+	assertFilter(t, Filter().Near("location", -46.63, -23.55, 2000).Build(), M{
+		"location": M{"$near": M{
+			"$geometry":    M{"type": "Point", "coordinates": []float64{-46.63, -23.55}},
+			"$maxDistance": 2000.0,
+		}},
+	})
+	assertFilter(t, Filter().Near("location", 1, 2, 0).Build(), M{
+		"location": M{"$near": M{"$geometry": M{"type": "Point", "coordinates": []float64{1, 2}}}},
+	})
+}
+
+func TestGeoWithin(t *testing.T) {
+	area := M{"type": "Polygon", "coordinates": [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}}
+	assertFilter(t, Filter().GeoWithin("location", area).Build(), M{
+		"location": M{"$geoWithin": M{"$geometry": area}},
+	})
+}
+
+// TestNearIntegration confirma, com um índice 2dsphere real, que Near usa (lng, lat) e
+// retorna do mais próximo ao mais distante. O ponto "swapped" fica perto de (lat, lng)
+// invertidos: só apareceria se a ordem das coordenadas estivesse trocada.
+func TestNearIntegration(t *testing.T) {
+	type venue struct {
+		Name     string `bson:"name"`
+		Location M      `bson:"location"`
+	}
+	ctx := context.Background()
+	venues := New[venue](integrationDB(t), "venues")
+	if _, err := venues.CreateIndex(ctx, D{{Key: "location", Value: "2dsphere"}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := venues.InsertMany(ctx, []venue{
+		{Name: "far", Location: GeoPoint(10.03, 0)},
+		{Name: "swapped", Location: GeoPoint(0, 10)},
+		{Name: "near", Location: GeoPoint(10.001, 0)},
+		{Name: "mid", Location: GeoPoint(10.01, 0)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := venues.FindAll(ctx, Filter().Near("location", 10, 0, 5000), nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(got))
+	for i, v := range got {
+		names[i] = v.Name
+	}
+	if len(names) != 3 || names[0] != "near" || names[1] != "mid" || names[2] != "far" {
+		t.Errorf("Near = %v, esperado [near mid far]", names)
+	}
+}
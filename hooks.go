@@ -0,0 +1,77 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: hooks.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define os hooks de ciclo de vida: interfaces opcionais que
+	o model (ou o patch) implementa para rodar validação, normalização ou
+	criptografia antes das escritas e depois das leituras.
+*/
+package monger
+
+import "context"
+
+// BeforeInserter é implementado por models que precisam validar ou preparar os dados antes
+// de serem inseridos (InsertOne, InsertMany, InsertWithID, Bulk().Insert). Um erro cancela
+// a inserção.
+//
+// Exemplo de uso:
+//
+//	func (u *User) BeforeInsert(ctx context.Context) error {
+//	    u.Email = strings.ToLower(u.Email)
+//	    return validate.Struct(u)
+//	}
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// BeforeUpdater é implementado pelo valor de update (patch ou model) que precisa validar ou
+// preparar os dados antes de uma atualização (UpdateByID, UpdateMany, Upsert,
+// FindOneAndUpdate, InsertOneAndUpdate, UpdateIfNewer, Transform, Bulk). Um erro cancela a
+// atualização. MergePatchByID não chama o hook (o patch é JSON, não um tipo Go).
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterFinder é implementado por tipos que precisam ajustar os dados depois de lidos (ex.:
+// descriptografar campos). É chamado para cada documento decodificado pelas leituras do
+// repositório — inclusive nos tipos de resultado de FindAs, AggregateAs e Run, e nos
+// elementos do slice de Aggregate e Pipeline.Decode. Um erro cancela a leitura.
+type AfterFinder interface {
+	AfterFind(ctx context.Context) error
+}
+
+// beforeInsert chama BeforeInsert se v implementar BeforeInserter.
+func beforeInsert(ctx context.Context, v any) error {
+	if h, ok := v.(BeforeInserter); ok {
+		return h.BeforeInsert(ctx)
+	}
+	return nil
+}
+
+// beforeUpdate chama BeforeUpdate se v implementar BeforeUpdater.
+func beforeUpdate(ctx context.Context, v any) error {
+	if h, ok := v.(BeforeUpdater); ok {
+		return h.BeforeUpdate(ctx)
+	}
+	return nil
+}
+
+// afterFind chama AfterFind se v implementar AfterFinder.
+func afterFind(ctx context.Context, v any) error {
+	if h, ok := v.(AfterFinder); ok {
+		return h.AfterFind(ctx)
+	}
+	return nil
+}
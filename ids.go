@@ -0,0 +1,140 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: ids.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define a conversão dos ids recebidos e retornados pelo
+	Repository (FindByID, UpdateByID, InsertOne, ...) para o tipo de _id da
+	coleção: ObjectID por padrão, ou strings, inteiros e tipos próprios.
+*/
+package monger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IDCodec converte entre o _id gravado na coleção e a string usada pelos métodos que
+// recebem ou retornam ids (FindByID, UpdateByID, DeleteByID, InsertOne, InsertMany, ...).
+//
+// Parse converte o id recebido no valor do _id (um erro vira ErrInvalidID); Format faz o
+// caminho inverso. Format pode ser nil: o padrão aceita ObjectID (hex), strings e inteiros.
+type IDCodec struct {
+	Parse  func(id string) (any, error)
+	Format func(id any) (string, error)
+}
+
+// WithIDCodec define como os ids do repositório são convertidos (veja IDCodec). Sem essa
+// opção (nem WithStringIDs/WithIntIDs), os ids são ObjectIDs em hex.
+//
+// Exemplo de uso:
+//
+//	// _id UUID gravado como string em maiúsculas
+//	devices := monger.New[Device](db, "devices", monger.WithIDCodec(monger.IDCodec{
+//	    Parse: func(id string) (any, error) {
+//	        u, err := uuid.Parse(id)
+//	        return strings.ToUpper(u.String()), err
+//	    },
+//	}))
+func WithIDCodec(codec IDCodec) Option {
+	return func(c *config) { c.idCodec = codec }
+}
+
+// WithStringIDs usa o id recebido como está: para coleções com _id string (slugs, códigos
+// naturais). Ids vazios são inválidos.
+func WithStringIDs() Option {
+	return WithIDCodec(IDCodec{Parse: func(id string) (any, error) {
+		if id == "" {
+			return nil, fmt.Errorf("id vazio")
+		}
+		return id, nil
+	}})
+}
+
+// WithIntIDs converte os ids recebidos em inteiros (int64), para coleções com _id numérico.
+// Como o servidor compara números independentemente do tipo, _ids gravados como int32
+// também são encontrados.
+func WithIntIDs() Option {
+	return WithIDCodec(IDCodec{Parse: func(id string) (any, error) {
+		return strconv.ParseInt(id, 10, 64)
+	}})
+}
+
+// parseID converte um id recebido no _id da coleção, retornando um erro que satisfaz
+// errors.Is(err, ErrInvalidID) quando o id é inválido.
+func (c *config) parseID(id string) (any, error) {
+	parse := c.idCodec.Parse
+	if parse == nil {
+		parse = parseObjectID
+	}
+	v, err := parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidID, id, err)
+	}
+	return v, nil
+}
+
+// parseIDs converte uma lista de ids, retornando erro com todos os ids inválidos.
+func (c *config) parseIDs(ids []string) ([]any, error) {
+	out := make([]any, 0, len(ids))
+	invalid := []string{}
+	for _, id := range ids {
+		v, err := c.parseID(id)
+		if err != nil {
+			invalid = append(invalid, id)
+			continue
+		}
+		out = append(out, v)
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidID, strings.Join(invalid, ", "))
+	}
+	return out, nil
+}
+
+// formatID converte um _id retornado pelo servidor na string devolvida ao chamador.
+func (c *config) formatID(v any) (string, error) {
+	if c.idCodec.Format != nil {
+		return c.idCodec.Format(v)
+	}
+	switch id := v.(type) {
+	case primitive.ObjectID:
+		return id.Hex(), nil
+	case string:
+		return id, nil
+	case int32:
+		return strconv.FormatInt(int64(id), 10), nil
+	case int64:
+		return strconv.FormatInt(id, 10), nil
+	case int:
+		return strconv.Itoa(id), nil
+	}
+	return "", fmt.Errorf("o _id (%T) não é ObjectID, string nem inteiro; use WithIDCodec", v)
+}
+
+// idString é formatID sem erro, para mensagens e callbacks: tipos sem conversão usam fmt.Sprint.
+func (c *config) idString(v any) string {
+	if s, err := c.formatID(v); err == nil {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// parseObjectID é o Parse padrão: o id é o hex de um ObjectID.
+func parseObjectID(id string) (any, error) {
+	return primitive.ObjectIDFromHex(id)
+}
@@ -0,0 +1,134 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: immutable.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define os campos imutáveis (somente leitura após a criação):
+	proteção contra mass-assignment nas atualizações parciais.
+*/
+package monger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// immutableTagFields retorna os nomes bson dos campos de t marcados com `monger:"immutable"`
+// (incluindo structs ",inline").
+func immutableTagFields(t reflect.Type) []string {
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name, inline := parseBsonTag(sf.Tag.Get("bson"))
+		if inline {
+			fields = append(fields, immutableTagFields(sf.Type)...)
+			continue
+		}
+		if name == "-" || !hasTagOption(sf.Tag.Get("monger"), "immutable") {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+// hasTagOption indica se a tag (lista separada por vírgulas) contém a opção informada.
+func hasTagOption(tag, option string) bool {
+	for _, opt := range strings.Split(tag, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isImmutablePath indica se gravar em path altera um campo imutável: o próprio campo, um
+// sub-campo dele ("owner.id" com "owner" imutável) ou um documento que o contém ("owner"
+// com "owner.id" imutável).
+func (r *Repository[T]) isImmutablePath(path string) bool {
+	for _, f := range r.cfg.immutableFields {
+		if path == f || strings.HasPrefix(path, f+".") || strings.HasPrefix(f, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// stripImmutable remove do documento de $set (ou $unset) os campos imutáveis. Com
+// WithRejectImmutable, retorna ErrImmutableField em vez de removê-los.
+func (r *Repository[T]) stripImmutable(doc M) error {
+	if len(r.cfg.immutableFields) == 0 {
+		return nil
+	}
+	for path := range doc {
+		if !r.isImmutablePath(path) {
+			continue
+		}
+		if r.cfg.rejectImmutable {
+			return fmt.Errorf("%w: %s", ErrImmutableField, path)
+		}
+		delete(doc, path)
+	}
+	return nil
+}
+
+// splitImmutable separa, em um upsert, os campos que podem ser atualizados ($set) dos
+// imutáveis, que são gravados apenas se o documento for criado ($setOnInsert).
+func (r *Repository[T]) splitImmutable(doc M) (set, onInsert M) {
+	set, onInsert = M{}, M{}
+	for path, v := range doc {
+		if r.isImmutablePath(path) {
+			onInsert[path] = v
+		} else {
+			set[path] = v
+		}
+	}
+	return set, onInsert
+}
+
+// keepImmutable copia para o documento de substituição os valores imutáveis do documento
+// original (ou os remove, se o original não os tinha), para que um replace não os altere.
+func (r *Repository[T]) keepImmutable(replacement M, original bson.Raw) error {
+	for _, f := range r.cfg.immutableFields {
+		path := strings.Split(f, ".")
+		if len(path) > 1 {
+			// campos aninhados: preserva o documento de primeiro nível inteiro
+			f = path[0]
+		}
+		val, err := original.LookupErr(f)
+		if err != nil {
+			delete(replacement, f)
+			continue
+		}
+		var v any
+		if err := val.Unmarshal(&v); err != nil {
+			return err
+		}
+		replacement[f] = v
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: indexes.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o gerenciamento de índices da coleção (criação,
+	listagem e remoção), para que eles sejam declarados junto com o código
+	em vez de mantidos à parte.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexOption configura um índice criado com CreateIndex.
+type IndexOption func(*options.IndexOptions)
+
+// IndexName define o nome do índice (padrão do servidor: campos e direções, ex.: "email_1").
+func IndexName(name string) IndexOption {
+	return func(o *options.IndexOptions) { o.SetName(name) }
+}
+
+// IndexUnique faz o índice rejeitar valores repetidos.
+func IndexUnique() IndexOption {
+	return func(o *options.IndexOptions) { o.SetUnique(true) }
+}
+
+// IndexSparse faz o índice ignorar documentos sem os campos indexados.
+func IndexSparse() IndexOption {
+	return func(o *options.IndexOptions) { o.SetSparse(true) }
+}
+
+// IndexTTL faz o servidor remover os documentos depois de ttl contado a partir do campo de
+// data indexado (índice de um único campo).
+func IndexTTL(ttl time.Duration) IndexOption {
+	return func(o *options.IndexOptions) { o.SetExpireAfterSeconds(int32(ttl / time.Second)) }
+}
+
+// IndexPartial restringe o índice aos documentos que satisfazem o filtro
+// (ex.: unicidade só entre os não excluídos).
+func IndexPartial(f *FilterBuilder) IndexOption {
+	return func(o *options.IndexOptions) {
+		if f != nil {
+			o.SetPartialFilterExpression(f.Build())
+		}
+	}
+}
+
+// IndexCollation define a collation do índice; consultas com a mesma collation (WithCollation,
+// UseCollation) podem usá-lo.
+func IndexCollation(c *options.Collation) IndexOption {
+	return func(o *options.IndexOptions) { o.SetCollation(c) }
+}
+
+// CreateIndex cria um índice com as chaves informadas (use D para preservar a ordem) e
+// retorna o nome dele. Criar de novo um índice com as mesmas chaves e opções não faz nada;
+// com as mesmas chaves e opções diferentes, o servidor retorna erro.
+//
+// Exemplo de uso:
+//
+//	name, err := orders.CreateIndex(ctx, monger.D{{Key: "customerId", Value: 1}, {Key: "createdAt", Value: -1}})
+//	_, err = sessions.CreateIndex(ctx, monger.D{{Key: "expiresAt", Value: 1}}, monger.IndexTTL(0))
+func (r *Repository[T]) CreateIndex(ctx context.Context, keys D, opts ...IndexOption) (name string, err error) {
+	ctx, span := r.startSpan(ctx, "CreateIndex")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if len(keys) == 0 {
+		return "", fmt.Errorf("keys não pode ser vazio")
+	}
+	o := options.Index()
+	for _, opt := range opts {
+		opt(o)
+	}
+	name, err = r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: o})
+	if err != nil {
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && (cmdErr.Code == 85 || cmdErr.Code == 86) { // IndexOptionsConflict, IndexKeySpecsConflict
+			return "", fmt.Errorf("já existe um índice com as chaves {%s} e opções diferentes: %w", describeSort(keys), err)
+		}
+		return "", err
+	}
+	r.resetIndexCache()
+	return name, nil
+}
+
+// EnsureUniqueIndex garante um índice único (crescente) nos campos informados — um índice
+// composto quando há mais de um campo. É idempotente: se o índice já existir com a mesma
+// especificação, nada é feito. Se existir um índice nos mesmos campos sem unique, retorna
+// erro (remova-o com DropIndex antes). Se a coleção já tiver valores repetidos, a criação
+// falha com o erro de chave duplicada do servidor.
+//
+// Exemplo de uso:
+//
+//	err := users.EnsureUniqueIndex(ctx, "email")
+func (r *Repository[T]) EnsureUniqueIndex(ctx context.Context, fields ...string) error {
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if len(fields) == 0 {
+		return fmt.Errorf("informe ao menos um campo")
+	}
+	keys := make(D, len(fields))
+	for i, f := range fields {
+		keys[i].Key, keys[i].Value = f, 1
+	}
+	_, err := r.CreateIndex(ctx, keys, IndexUnique())
+	return err
+}
+
+// ListIndexes retorna a especificação de cada índice da coleção (name, key, unique, ...),
+// como retornada pelo servidor.
+func (r *Repository[T]) ListIndexes(ctx context.Context) (specs []M, err error) {
+	ctx, span := r.startSpan(ctx, "ListIndexes")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	cursor, err := r.coll.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	specs = []M{}
+	if err := cursor.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// DropIndex remove o índice com o nome informado (veja ListIndexes). O índice de _id não
+// pode ser removido.
+func (r *Repository[T]) DropIndex(ctx context.Context, name string) (err error) {
+	ctx, span := r.startSpan(ctx, "DropIndex")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if name == "" {
+		return fmt.Errorf("name não pode ser vazio")
+	}
+	if _, err := r.coll.Indexes().DropOne(ctx, name); err != nil {
+		return err
+	}
+	r.resetIndexCache()
+	return nil
+}
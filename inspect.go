@@ -0,0 +1,346 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: inspect.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define utilitários de inspeção de filtros já montados,
+	úteis para validação (allowlist de campos), logs e auditoria.
+*/
+package monger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fields retorna os nomes dos campos do documento referenciados pelo filtro, sem repetição
+// e em ordem alfabética. A busca é recursiva: entra em $and/$or/$nor, em sub-filtros de
+// $elemMatch (retornados com caminho pontuado, ex.: "items.price") e em referências
+// "$campo" dentro de $expr.
+//
+// Exemplo de uso:
+//
+//	f := monger.Filter().Eq("status", "active").Or(
+//	    monger.Filter().Gt("age", 18),
+//	    monger.Filter().Eq("role", "admin"),
+//	)
+//	f.Fields() // ["age", "role", "status"]
+func (b *FilterBuilder) Fields() []string {
+	seen := map[string]bool{}
+	collectFilterFields(b.Build(), "", seen)
+
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// collectFilterFields percorre um documento de filtro registrando os campos encontrados.
+func collectFilterFields(filter any, prefix string, seen map[string]bool) {
+	for _, e := range docElements(filter) {
+		switch {
+		case e.Key == "$expr":
+			collectExprFields(e.Value, seen)
+		case strings.HasPrefix(e.Key, "$"):
+			// $and, $or, $nor: lista de sub-filtros
+			for _, sub := range listElements(e.Value) {
+				collectFilterFields(sub, prefix, seen)
+			}
+		default:
+			field := prefix + e.Key
+			seen[field] = true
+			collectOperatorFields(e.Value, field, seen)
+		}
+	}
+}
+
+// collectOperatorFields entra nos operadores de um campo que carregam sub-filtros ($elemMatch, $not, $all).
+func collectOperatorFields(expr any, field string, seen map[string]bool) {
+	for _, e := range docElements(expr) {
+		switch e.Key {
+		case "$elemMatch":
+			collectFilterFields(e.Value, field+".", seen)
+		case "$not":
+			collectOperatorFields(e.Value, field, seen)
+		case "$all":
+			for _, item := range listElements(e.Value) {
+				collectOperatorFields(item, field, seen)
+			}
+		}
+	}
+}
+
+// collectExprFields registra as referências "$campo" de uma expressão de agregação
+// (variáveis "$$var" são ignoradas).
+func collectExprFields(expr any, seen map[string]bool) {
+	switch v := expr.(type) {
+	case string:
+		if strings.HasPrefix(v, "$") && !strings.HasPrefix(v, "$$") {
+			seen[strings.TrimPrefix(v, "$")] = true
+		}
+	default:
+		if elems := docElements(v); elems != nil {
+			for _, e := range elems {
+				collectExprFields(e.Value, seen)
+			}
+			return
+		}
+		for _, item := range listElements(v) {
+			collectExprFields(item, seen)
+		}
+	}
+}
+
+// Describe retorna uma descrição legível do filtro, para logs de auditoria e telas de
+// administração (ex.: "status = active AND age > 18"). A descrição é apenas para exibição
+// e não pode ser convertida de volta em filtro.
+//
+// Condições do mesmo nível são unidas por AND (em ordem alfabética dos campos, para que a
+// saída seja estável); grupos $or/$and/$nor aninhados ficam entre parênteses. Operadores
+// sem representação própria são exibidos como "campo $op valor". Um filtro vazio retorna "".
+//
+// Exemplo de uso:
+//
+//	f := monger.Filter().Eq("status", "active").Or(
+//	    monger.Filter().Gt("age", 18),
+//	    monger.Filter().In("role", []string{"admin", "owner"}),
+//	)
+//	f.Describe() // "(age > 18 OR role IN (admin, owner)) AND status = active"
+func (b *FilterBuilder) Describe() string {
+	return describeFilter(b.Build())
+}
+
+// describeOps mapeia operadores de comparação para sua forma legível.
+var describeOps = map[string]string{
+	"$eq":  "=",
+	"$ne":  "!=",
+	"$gt":  ">",
+	"$gte": ">=",
+	"$lt":  "<",
+	"$lte": "<=",
+	"$in":  "IN",
+	"$nin": "NOT IN",
+}
+
+// describeFilter descreve um documento de filtro, unindo suas condições com AND.
+func describeFilter(filter any) string {
+	return strings.Join(describeParts(filter), " AND ")
+}
+
+// describeParts descreve cada condição de um documento de filtro separadamente.
+func describeParts(filter any) []string {
+	elems := sortedElements(filter)
+	parts := make([]string, 0, len(elems))
+	for _, e := range elems {
+		switch e.Key {
+		case "$and", "$or", "$nor":
+			if part := describeGroup(e.Key, e.Value); part != "" {
+				parts = append(parts, part)
+			}
+		case "$expr":
+			parts = append(parts, "EXPR "+describeValue(e.Value))
+		default:
+			parts = append(parts, describeField(e.Key, e.Value)...)
+		}
+	}
+	return parts
+}
+
+// describeGroup descreve um grupo lógico. Grupos $or/$nor com mais de um elemento ficam
+// entre parênteses, assim como cada elemento composto por mais de uma condição.
+func describeGroup(op string, value any) string {
+	items := listElements(value)
+	if items == nil {
+		items = reflectList(value)
+	}
+	subs := []string{}
+	for _, item := range items {
+		parts := describeParts(item)
+		switch {
+		case len(parts) == 0:
+			continue
+		case len(parts) > 1 && op != "$and":
+			subs = append(subs, "("+strings.Join(parts, " AND ")+")")
+		default:
+			subs = append(subs, strings.Join(parts, " AND "))
+		}
+	}
+	switch {
+	case len(subs) == 0:
+		return ""
+	case op == "$nor":
+		return "NOT (" + strings.Join(subs, " OR ") + ")"
+	case op == "$or" && len(subs) > 1:
+		return "(" + strings.Join(subs, " OR ") + ")"
+	}
+	return strings.Join(subs, " AND ")
+}
+
+// describeField descreve as condições de um campo (igualdade ou documento de operadores).
+func describeField(field string, value any) []string {
+	ops := sortedElements(value)
+	if len(ops) == 0 || !strings.HasPrefix(ops[0].Key, "$") {
+		return []string{field + " = " + describeValue(value)}
+	}
+
+	parts := make([]string, 0, len(ops))
+	for _, op := range ops {
+		switch op.Key {
+		case "$exists":
+			if b, ok := op.Value.(bool); ok && !b {
+				parts = append(parts, field+" NOT EXISTS")
+			} else {
+				parts = append(parts, field+" EXISTS")
+			}
+		case "$regex":
+			pattern := describeValue(op.Value)
+			if p, ok := op.Value.(string); ok {
+				pattern = "/" + p + "/" + regexOptions(ops)
+			}
+			parts = append(parts, field+" ~ "+pattern)
+		case "$options":
+			// exibido junto com $regex
+		case "$not":
+			parts = append(parts, "NOT ("+strings.Join(describeField(field, op.Value), " AND ")+")")
+		case "$elemMatch":
+			parts = append(parts, field+" HAS ("+describeFilter(op.Value)+")")
+		default:
+			if sym, ok := describeOps[op.Key]; ok {
+				parts = append(parts, field+" "+sym+" "+describeValue(op.Value))
+			} else {
+				parts = append(parts, field+" "+op.Key+" "+describeValue(op.Value))
+			}
+		}
+	}
+	return parts
+}
+
+// describeValue formata um valor para exibição.
+func describeValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		if t == "" {
+			return `""`
+		}
+		return t
+	case primitive.ObjectID:
+		return t.Hex()
+	case time.Time:
+		return t.Format(time.RFC3339)
+	case primitive.DateTime:
+		return t.Time().UTC().Format(time.RFC3339)
+	case primitive.Regex:
+		return "/" + t.Pattern + "/" + t.Options
+	}
+	if items := listElements(v); items != nil {
+		return describeList(items)
+	}
+	if elems := sortedElements(v); elems != nil {
+		parts := make([]string, len(elems))
+		for i, e := range elems {
+			parts[i] = e.Key + ": " + describeValue(e.Value)
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+	if rv := reflectList(v); rv != nil {
+		return describeList(rv)
+	}
+	return fmt.Sprint(v)
+}
+
+// regexOptions retorna o $options que acompanha um $regex (vazio se não houver).
+func regexOptions(ops []bson.E) string {
+	for _, op := range ops {
+		if op.Key == "$options" {
+			if o, ok := op.Value.(string); ok {
+				return o
+			}
+		}
+	}
+	return ""
+}
+
+// describeList formata uma lista de valores como "(a, b, c)".
+func describeList(items []any) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = describeValue(item)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// sortedElements retorna os elementos de um documento; para M, em ordem alfabética das chaves.
+func sortedElements(v any) []bson.E {
+	elems := docElements(v)
+	if _, ok := v.(M); ok {
+		sort.Slice(elems, func(i, j int) bool { return elems[i].Key < elems[j].Key })
+	}
+	return elems
+}
+
+// docElements retorna os pares chave/valor de um documento (M ou D), ou nil se não for documento.
+func docElements(v any) []bson.E {
+	switch d := v.(type) {
+	case M:
+		elems := make([]bson.E, 0, len(d))
+		for k, val := range d {
+			elems = append(elems, bson.E{Key: k, Value: val})
+		}
+		return elems
+	case D:
+		return d
+	}
+	return nil
+}
+
+// listElements retorna os itens de uma lista ([]M, []any, bson.A), ou nil se não for lista.
+func listElements(v any) []any {
+	switch l := v.(type) {
+	case []M:
+		items := make([]any, len(l))
+		for i, m := range l {
+			items[i] = m
+		}
+		return items
+	case []any:
+		return l
+	case bson.A:
+		return l
+	}
+	return nil
+}
+
+// reflectList converte slices/arrays de qualquer tipo (ex.: []string) em []any.
+// Retorna nil se v não for lista; []byte não é considerado lista.
+func reflectList(v any) []any {
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil
+	}
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
@@ -0,0 +1,328 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: iterate.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define a iteração documento a documento sobre coleções
+	grandes (jobs de exportação/processamento em lote), sem carregar tudo
+	em memória.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Iterate percorre os documentos que satisfazem o filtro (nil = todos) um a um, chamando fn
+// para cada documento, sem carregar o resultado inteiro em memória como FindAll. Usa a
+// ordenação padrão do repositório (WithDefaultSort), se houver.
+//
+// Se fn retornar ErrStopIteration, a iteração para e Iterate retorna nil; qualquer outro erro
+// interrompe a iteração e é retornado. Cada chamada recebe um documento novo, então fn pode
+// guardar o ponteiro.
+//
+// Exemplo de uso:
+//
+//	w := csv.NewWriter(out)
+//	err := orders.Iterate(ctx, monger.Filter().Eq("status", "paid"), nil, func(o *Order) error {
+//	    return w.Write([]string{o.ID, o.Customer, o.Total.String()})
+//	})
+func (r *Repository[T]) Iterate(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, fn func(doc *T) error) (err error) {
+	ctx, span := r.startSpan(ctx, "Iterate")
+	var docs int64
+	defer func() { span.end(err, docs) }()
+	if fn == nil {
+		return fmt.Errorf("fn não pode ser nil")
+	}
+	for doc, err := range r.stream(ctx, f, p) {
+		if err != nil {
+			return err
+		}
+		docs++
+		if err := fn(doc); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Stream é a versão range-over-func de Iterate: retorna um iter.Seq2 que produz os documentos
+// um a um. Um erro (da consulta, da decodificação ou de AfterFind) é produzido com doc nil e
+// encerra a sequência; sair do laço (break) fecha o cursor.
+//
+// Exemplo de uso:
+//
+//	for user, err := range users.Stream(ctx, nil, nil) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    export(user)
+//	}
+func (r *Repository[T]) Stream(ctx context.Context, f *FilterBuilder, p *ProjectBuilder) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		ctx, span := r.startSpan(ctx, "Stream")
+		var docs int64
+		var err error
+		defer func() { span.end(err, docs) }()
+		for doc, e := range r.stream(ctx, f, p) {
+			if e != nil {
+				err = e
+				yield(nil, err)
+				return
+			}
+			docs++
+			if !yield(doc, nil) {
+				return
+			}
+		}
+	}
+}
+
+// stream produz os documentos do filtro um a um, como Stream (o span fica com quem chama).
+func (r *Repository[T]) stream(ctx context.Context, f *FilterBuilder, p *ProjectBuilder) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		filter, err := r.readFilter(ctx, f)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		opts := options.Find()
+		if p != nil {
+			opts.SetProjection(p.Build())
+		}
+		if sort := r.sortOrDefault(nil); sort != nil {
+			opts.SetSort(sort)
+			r.checkSortIndex(ctx, sort)
+		}
+		cursor, err := r.coll.Find(ctx, filter, opts)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			doc := new(T)
+			if err := cursor.Decode(doc); err != nil {
+				yield(nil, wrapDecodeError(r.coll.Name(), cursor.Current, err))
+				return
+			}
+			if err := afterFind(ctx, doc); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(doc, nil) {
+				return
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// ForEachResumable percorre, em ordem crescente de _id, os documentos que satisfazem o filtro,
+// chamando fn para cada um. A cada checkpointEvery documentos processados (e ao final),
+// onCheckpoint recebe o _id (hex, ou conforme WithIDCodec) do último documento processado com
+// sucesso; persista esse valor e passe-o em resumeFrom para continuar de onde parou após uma
+// falha ("" começa do início).
+//
+// Como a retomada usa {_id: {$gt: resumeFrom}}, a iteração usa o índice de _id e não depende
+// de cursores longos. Os _ids devem ser de um único tipo (a ordem entre tipos diferentes segue
+// a do BSON) e a projeção não pode excluir o _id.
+//
+// Se fn ou onCheckpoint retornar erro, a iteração para e o erro é retornado (o erro de fn vem
+// com o _id do documento). onCheckpoint pode ser nil.
+//
+// Exemplo de uso:
+//
+//	err := orders.ForEachResumable(ctx, nil, nil, loadCheckpoint(), 1000,
+//	    func(o *Order) error { return writeCSV(o) },
+//	    func(lastID string) error { return saveCheckpoint(lastID) },
+//	)
+func (r *Repository[T]) ForEachResumable(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, resumeFrom string, checkpointEvery int, fn func(doc *T) error, onCheckpoint func(lastID string) error) (err error) {
+	ctx, span := r.startSpan(ctx, "ForEachResumable")
+	var processed int64
+	defer func() { span.end(err, processed) }()
+	if fn == nil {
+		return fmt.Errorf("fn não pode ser nil")
+	}
+	if checkpointEvery <= 0 {
+		checkpointEvery = 1000
+	}
+
+	filter := M{}
+	if f != nil {
+		filter = f.Build()
+	}
+	if resumeFrom != "" {
+		oid, err := r.cfg.parseID(resumeFrom)
+		if err != nil {
+			return fmt.Errorf("resumeFrom inválido: %w", err)
+		}
+		filter = andFilters(filter, M{"_id": M{"$gt": oid}})
+	}
+	filter, err = r.scopeFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().SetSort(D{{Key: "_id", Value: 1}})
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	lastID := ""
+	pending := 0
+	for cursor.Next(ctx) {
+		id, err := r.rawID(cursor.Current)
+		if err != nil {
+			return err
+		}
+
+		var doc T
+		if err := cursor.Decode(&doc); err != nil {
+			return wrapDecodeError(r.coll.Name(), cursor.Current, err)
+		}
+		if err := afterFind(ctx, &doc); err != nil {
+			return err
+		}
+		if err := fn(&doc); err != nil {
+			return fmt.Errorf("documento %s: %w", id, err)
+		}
+
+		lastID = id
+		processed++
+		pending++
+		if pending >= checkpointEvery && onCheckpoint != nil {
+			if err := onCheckpoint(lastID); err != nil {
+				return err
+			}
+			pending = 0
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if pending > 0 && onCheckpoint != nil {
+		return onCheckpoint(lastID)
+	}
+	return nil
+}
+
+// ForEachWithProgress percorre os documentos que satisfazem o filtro chamando fn para cada um,
+// e a cada every documentos (padrão 1000) e ao final chama onProgress com a quantidade
+// processada e o total previsto — útil para barras de progresso em jobs de manutenção.
+//
+// O total é calculado antes da iteração: sem filtro (e sem restrições do repositório), vem de
+// EstimatedDocumentCount (barato, aproximado); com filtro, de uma contagem do filtro (exata
+// naquele instante, mas com o custo de um Count). Documentos inseridos durante a iteração
+// podem fazer processed passar de total.
+//
+// Se fn retornar erro, a iteração para e o erro é retornado. onProgress pode ser nil.
+//
+// Exemplo de uso:
+//
+//	err := users.ForEachWithProgress(ctx, nil, nil, 500, reindex, func(processed, total int64) {
+//	    log.Printf("processados %d de ~%d", processed, total)
+//	})
+func (r *Repository[T]) ForEachWithProgress(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, every int, fn func(doc *T) error, onProgress func(processed, total int64)) (err error) {
+	ctx, span := r.startSpan(ctx, "ForEachWithProgress")
+	var processed int64
+	defer func() { span.end(err, processed) }()
+	if fn == nil {
+		return fmt.Errorf("fn não pode ser nil")
+	}
+	if every <= 0 {
+		every = 1000
+	}
+	filter, err := r.readFilter(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	if onProgress != nil {
+		if len(filter) == 0 {
+			total, err = r.coll.EstimatedDocumentCount(ctx)
+		} else {
+			total, err = r.coll.CountDocuments(ctx, filter)
+		}
+		if err != nil {
+			return err
+		}
+		onProgress(0, total)
+	}
+
+	opts := options.Find()
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc T
+		if err := cursor.Decode(&doc); err != nil {
+			return wrapDecodeError(r.coll.Name(), cursor.Current, err)
+		}
+		if err := afterFind(ctx, &doc); err != nil {
+			return err
+		}
+		if err := fn(&doc); err != nil {
+			return err
+		}
+		processed++
+		if onProgress != nil && processed%int64(every) == 0 {
+			onProgress(processed, total)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if onProgress != nil && processed%int64(every) != 0 {
+		onProgress(processed, total)
+	}
+	return nil
+}
+
+// rawID extrai o _id de um documento como string (hex, ou conforme WithIDCodec).
+func (r *Repository[T]) rawID(raw bson.Raw) (string, error) {
+	val, err := raw.LookupErr("_id")
+	if err != nil {
+		return "", fmt.Errorf("documento sem _id: a projeção não pode excluir o _id")
+	}
+	var id any
+	if err := val.Unmarshal(&id); err != nil {
+		return "", err
+	}
+	return r.cfg.idString(id), nil
+}
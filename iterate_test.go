@@ -0,0 +1,72 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: iterate_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes das iterações (ForEachResumable), sobre um servidor simulado.
+*/
+package monger
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestForEachResumableIDCodecs(t *testing.T) {
+	type row struct {
+		ID any `bson:"_id"`
+	}
+	tests := []struct {
+		name       string
+		opts       []Option
+		resumeFrom string
+		wantGt     any // _id do $gt enviado ao servidor
+		ids        []any
+		want       []string
+	}{
+		{"WithIntIDs", []Option{WithIntIDs()}, "5", int64(5), []any{int64(6), int64(7)}, []string{"6", "7"}},
+		{"WithStringIDs", []Option{WithStringIDs()}, "b", "b", []any{"c", "d"}, []string{"c", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo(t, tt.opts, func(t *testing.T, mt *mtest.T, r *Repository[row]) {
+				docs := make([]bson.D, len(tt.ids))
+				for i, id := range tt.ids {
+					docs[i] = bson.D{{Key: "_id", Value: id}}
+				}
+				mt.AddMockResponses(cursorReply(mt, docs...))
+
+				var checkpoints []string
+				err := r.ForEachResumable(context.Background(), nil, nil, tt.resumeFrom, 1,
+					func(*row) error { return nil },
+					func(lastID string) error { checkpoints = append(checkpoints, lastID); return nil },
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(checkpoints, tt.want) {
+					t.Errorf("checkpoints = %v, esperado %v", checkpoints, tt.want)
+				}
+				var gt any
+				if err := sentCommand(t, mt).Lookup("filter", "_id", "$gt").Unmarshal(&gt); err != nil || gt != tt.wantGt {
+					t.Errorf("$gt = %#v, esperado %#v", gt, tt.wantGt)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: keyset.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define a paginação por cursor (keyset): cada página começa
+	depois do último documento da anterior, sem skip, com custo constante
+	mesmo em coleções grandes.
+*/
+package monger
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// KeysetToken é o token (opaco, base64 URL-safe) de paginação de FindAfter. É um tipo próprio
+// para diferenciar o token de um valor string do campo de ordenação: um token recebido como
+// string (ex.: de um parâmetro de URL) deve ser convertido com KeysetToken(s).
+type KeysetToken string
+
+// keysetCursor é o conteúdo (opaco para o chamador) do token de FindAfter.
+type keysetCursor struct {
+	Value any `bson:"v"`
+	ID    any `bson:"id"`
+}
+
+// FindAfter busca a próxima página de uma paginação por cursor (keyset), ordenada por
+// sortField e desempatada por _id. Diferente de FindPaged, não usa skip: a consulta começa
+// exatamente depois do último documento da página anterior, com custo constante mesmo em
+// coleções com milhões de documentos (crie um índice {sortField: 1, _id: 1}).
+//
+// sortField com prefixo "-" ordena de forma decrescente (ex.: "-createdAt"). afterValue é o
+// KeysetToken retornado pela chamada anterior (nil ou KeysetToken("") para a primeira página);
+// qualquer outro valor (inclusive uma string) é um valor do campo, para começar depois dele
+// (sem desempate). Retorna a página, com Total -1 (não é calculado), e o token da próxima
+// página — "" quando não há mais documentos.
+//
+// A projeção, se houver, é ajustada para trazer sortField e _id (necessários para o token).
+//
+// Exemplo de uso:
+//
+//	cursor := monger.KeysetToken(r.URL.Query().Get("cursor"))
+//	page, next, err := posts.FindAfter(ctx, nil, nil, "-createdAt", cursor, 50)
+//	// responda page.Data e next; o cliente envia next na próxima requisição
+//
+//	// depois de um valor do campo (ex.: nomes depois de "M")
+//	page, next, err = users.FindAfter(ctx, nil, nil, "name", "M", 50)
+func (r *Repository[T]) FindAfter(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, sortField string, afterValue any, limit int64) (page *PagedResult[T], next KeysetToken, err error) {
+	ctx, span := r.startSpan(ctx, "FindAfter")
+	defer func() { span.end(err, pageDocs(page)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	field, dir := strings.TrimPrefix(sortField, "-"), 1
+	if strings.HasPrefix(sortField, "-") {
+		dir = -1
+	}
+	if field == "" {
+		return nil, "", fmt.Errorf("sortField não pode ser vazio")
+	}
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("limit deve ser maior que zero")
+	}
+
+	filter, err := r.readFilter(ctx, f)
+	if err != nil {
+		return nil, "", err
+	}
+	if after, err := keysetFilter(field, dir, afterValue); err != nil {
+		return nil, "", err
+	} else if after != nil {
+		filter = andFilters(filter, after)
+	}
+
+	sort := D{{Key: field, Value: dir}}
+	if field != "_id" {
+		sort = append(sort, D{{Key: "_id", Value: dir}}...)
+	}
+	opts := options.Find().SetSort(sort).SetLimit(limit + 1)
+	if p != nil {
+		opts.SetProjection(keysetProjection(p.Build(), field))
+	}
+	r.checkSortIndex(ctx, sort)
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	data := []T{}
+	var last bson.Raw
+	for cursor.Next(ctx) {
+		if int64(len(data)) == limit {
+			// há pelo menos mais um documento: o token aponta para o último da página
+			next, err := encodeKeyset(last, field)
+			if err != nil {
+				return nil, "", err
+			}
+			return &PagedResult[T]{Data: data, Total: -1}, next, nil
+		}
+		var doc T
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, "", wrapDecodeError(r.coll.Name(), cursor.Current, err)
+		}
+		if err := afterFind(ctx, &doc); err != nil {
+			return nil, "", err
+		}
+		data = append(data, doc)
+		last = append(bson.Raw{}, cursor.Current...)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, "", err
+	}
+	return &PagedResult[T]{Data: data, Total: -1}, "", nil
+}
+
+// keysetFilter monta a condição "depois de afterValue" na direção dir.
+func keysetFilter(field string, dir int, afterValue any) (M, error) {
+	op := "$gt"
+	if dir < 0 {
+		op = "$lt"
+	}
+	switch v := afterValue.(type) {
+	case nil:
+		return nil, nil
+	case KeysetToken:
+		if v == "" {
+			return nil, nil
+		}
+		c, err := decodeKeyset(v)
+		if err != nil {
+			return nil, err
+		}
+		if field == "_id" {
+			return M{"_id": M{op: c.ID}}, nil
+		}
+		return M{"$or": []M{
+			{field: M{op: c.Value}},
+			{field: c.Value, "_id": M{op: c.ID}},
+		}}, nil
+	default:
+		return M{field: M{op: v}}, nil
+	}
+}
+
+// keysetProjection garante que a projeção traga o campo de ordenação e o _id.
+func keysetProjection(projection M, field string) M {
+	out := make(M, len(projection)+2)
+	inclusion := false
+	for k, v := range projection {
+		out[k] = v
+		if k != "_id" && !isExcluded(v) && !isSlice(v) {
+			inclusion = true
+		}
+	}
+	delete(out, "_id")
+	if !inclusion {
+		delete(out, field) // exclusão (ou só _id: 0 / $slice): os demais campos já vêm
+		return out
+	}
+	out[field] = 1
+	return out
+}
+
+// isSlice indica se o valor de uma projeção é um {$slice: ...}, que não restringe os
+// demais campos.
+func isSlice(v any) bool {
+	m, ok := v.(M)
+	if !ok || len(m) != 1 {
+		return false
+	}
+	_, ok = m["$slice"]
+	return ok
+}
+
+// isExcluded indica se o valor de uma projeção exclui o campo (0 ou false).
+func isExcluded(v any) bool {
+	if b, ok := v.(bool); ok {
+		return !b
+	}
+	switch v.(type) {
+	case int, int32, int64, float64:
+		return asInt64(v) == 0
+	}
+	return false
+}
+
+// encodeKeyset gera o token com o valor de ordenação e o _id do documento.
+func encodeKeyset(raw bson.Raw, field string) (KeysetToken, error) {
+	val, err := raw.LookupErr(strings.Split(field, ".")...)
+	if err != nil {
+		return "", fmt.Errorf("documento sem o campo de ordenação %q", field)
+	}
+	var c keysetCursor
+	if err := val.Unmarshal(&c.Value); err != nil {
+		return "", err
+	}
+	if err := raw.Lookup("_id").Unmarshal(&c.ID); err != nil {
+		return "", err
+	}
+	b, err := bson.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return KeysetToken(base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+// decodeKeyset lê um token gerado por encodeKeyset.
+func decodeKeyset(token KeysetToken) (keysetCursor, error) {
+	var c keysetCursor
+	b, err := base64.RawURLEncoding.DecodeString(string(token))
+	if err != nil {
+		return c, fmt.Errorf("cursor inválido: %w", err)
+	}
+	if err := bson.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("cursor inválido: %w", err)
+	}
+	return c, nil
+}
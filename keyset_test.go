@@ -0,0 +1,66 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: keyset_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes da paginação por cursor (FindAfter), sem banco de dados.
+*/
+package monger
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestKeysetFilter(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{Key: "_id", Value: "p9"}, {Key: "name", Value: "Maria"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := encodeKeyset(raw, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		after any
+		dir   int
+		want  M
+	}{
+		{"primeira página (nil)", nil, 1, nil},
+		{"primeira página (token vazio)", KeysetToken(""), 1, nil},
+		{
+			"token com desempate por _id",
+			token, 1,
+			M{"$or": []M{{"name": M{"$gt": "Maria"}}, {"name": "Maria", "_id": M{"$gt": "p9"}}}},
+		},
+		{"string é valor do campo", "M", 1, M{"name": M{"$gt": "M"}}},
+		{"valor decrescente", "M", -1, M{"name": M{"$lt": "M"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keysetFilter("name", tt.dir, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertFilter(t, got, tt.want)
+		})
+	}
+
+	if _, err := keysetFilter("name", 1, KeysetToken("não é um token")); err == nil {
+		t.Error("token inválido deveria retornar erro")
+	}
+}
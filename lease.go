@@ -0,0 +1,171 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: lease.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define utilitários de "lease" (reserva temporária) de documentos,
+	úteis para filas de jobs distribuídas em que apenas um worker pode processar
+	cada documento por vez.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Campos gravados no documento para controlar o lease.
+const (
+	leaseOwnerField = "claimedBy"
+	leaseUntilField = "claimedUntil"
+)
+
+// Claim reserva atomicamente (FindOneAndUpdate) o primeiro documento que satisfaça o filtro
+// e que não esteja reservado — ou seja, cujo lease expirou ou nunca existiu.
+//
+// O documento reservado recebe os campos:
+//   - claimedBy: workerID
+//   - claimedUntil: agora + lease
+//
+// Retorna o documento já atualizado, ou ErrNotFound se não houver nada disponível.
+// Como a operação é atômica no servidor, apenas um worker consegue reservar cada documento.
+//
+// Exemplo de uso:
+//
+//	job, err := jobs.Claim(ctx, monger.Filter().Eq("status", "pending"), "worker-1", 30*time.Second)
+//	if errors.Is(err, monger.ErrNotFound) {
+//	    // fila vazia
+//	}
+func (r *Repository[T]) Claim(ctx context.Context, f *FilterBuilder, workerID string, lease time.Duration) (out *T, err error) {
+	ctx, span := r.startSpan(ctx, "Claim")
+	defer func() { span.end(err, found(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if workerID == "" {
+		return nil, fmt.Errorf("workerID não pode ser vazio")
+	}
+	if lease <= 0 {
+		return nil, fmt.Errorf("lease deve ser maior que zero")
+	}
+
+	now := time.Now()
+	// {claimedUntil: null} casa tanto o campo ausente quanto o valor nulo
+	available := M{"$or": []M{
+		{leaseUntilField: M{"$lt": now}},
+		{leaseUntilField: nil},
+	}}
+
+	filter := available
+	if f != nil {
+		filter = andFilters(f.Build(), available)
+	}
+	filter, err = r.scopeFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	set := M{
+		leaseOwnerField: workerID,
+		leaseUntilField: now.Add(lease),
+	}
+	r.touch(set)
+	update := r.setUpdate(set)
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	r.logOp("Claim", func() M { return M{"filter": filter, "update": update} })
+	res, err := decodeSingle[T](ctx, r.coll.FindOneAndUpdate(ctx, filter, update, opts), r.coll.Name())
+	if err != nil {
+		return nil, writeError(notFound(err))
+	}
+	return res, nil
+}
+
+// Heartbeat estende o lease de um documento reservado por workerID, definindo
+// claimedUntil para agora + lease.
+//
+// Só estende se o documento ainda pertencer ao worker e o lease atual não tiver expirado;
+// caso contrário retorna ErrLeaseLost (outro worker pode ter reservado o documento).
+func (r *Repository[T]) Heartbeat(ctx context.Context, id, workerID string, lease time.Duration) (err error) {
+	ctx, span := r.startSpan(ctx, "Heartbeat")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	oid, err := r.cfg.parseID(id)
+	if err != nil {
+		return err
+	}
+	if lease <= 0 {
+		return fmt.Errorf("lease deve ser maior que zero")
+	}
+
+	now := time.Now()
+	filter, err := r.scopeFilter(ctx, M{
+		"_id":           oid,
+		leaseOwnerField: workerID,
+		leaseUntilField: M{"$gte": now},
+	})
+	if err != nil {
+		return err
+	}
+	set := M{leaseUntilField: now.Add(lease)}
+	r.touch(set)
+	update := r.setUpdate(set)
+
+	r.logOp("Heartbeat", func() M { return M{"filter": filter, "update": update} })
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return writeError(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Release libera o lease de um documento reservado por workerID, removendo
+// os campos claimedBy e claimedUntil para que ele volte a ficar disponível.
+//
+// Retorna ErrLeaseLost se o documento não pertencer mais ao worker.
+func (r *Repository[T]) Release(ctx context.Context, id, workerID string) (err error) {
+	ctx, span := r.startSpan(ctx, "Release")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	oid, err := r.cfg.parseID(id)
+	if err != nil {
+		return err
+	}
+
+	filter, err := r.scopeFilter(ctx, M{"_id": oid, leaseOwnerField: workerID})
+	if err != nil {
+		return err
+	}
+	set := M{}
+	r.touch(set)
+	update := r.setUpdate(set)
+	update["$unset"] = M{leaseOwnerField: "", leaseUntilField: ""}
+
+	r.logOp("Release", func() M { return M{"filter": filter, "update": update} })
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return writeError(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
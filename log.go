@@ -0,0 +1,60 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: log.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o logger de depuração de consultas (WithLogger):
+	filtros, projeções, ordenações e updates exatamente como são enviados
+	ao MongoDB.
+*/
+package monger
+
+// WithLogger registra um logger chamado antes de cada operação do repositório com os
+// documentos exatamente como serão enviados ao servidor, já com as restrições do repositório
+// (soft-delete, WithRowSecurity, versão, datas automáticas). payload traz "collection" e,
+// conforme a operação, "filter", "projection", "sort", "skip", "limit", "update",
+// "pipeline", "document"/"documents" e "softDelete". Os avisos de WithExplainWarnings (com
+// warn nil) também chegam aqui, como a operação "Warning" com payload["message"].
+//
+// Sem WithLogger (padrão), nada é montado: o custo é só uma verificação de nil. O payload é
+// montado a cada chamada, mas os valores não são serializados; fn não deve alterá-los.
+//
+// Exemplo de uso:
+//
+//	users := monger.New[User](db, "users", monger.WithLogger(func(op string, payload monger.M) {
+//	    b, _ := bson.MarshalExtJSON(payload, false, false)
+//	    log.Printf("monger %s %s", op, b)
+//	}))
+func WithLogger(fn func(op string, payload M)) Option {
+	return func(c *config) { c.logger = fn }
+}
+
+// logOp envia ao logger de WithLogger a operação op com o payload montado por build; sem
+// logger, build não é chamado.
+func (r *Repository[T]) logOp(op string, build func() M) {
+	if r.cfg.logger == nil {
+		return
+	}
+	payload := build()
+	payload["collection"] = r.coll.Name()
+	r.cfg.logger(op, payload)
+}
+
+// projectionOf retorna o documento de projeção do builder (nil se não houver).
+func projectionOf(p *ProjectBuilder) any {
+	if p == nil {
+		return nil
+	}
+	return p.Build()
+}
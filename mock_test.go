@@ -0,0 +1,89 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: mock_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Utilitários dos testes que precisam de uma coleção: um servidor simulado
+	(mtest) que responde com documentos pré-definidos e registra os comandos
+	enviados, e um banco real (MONGODB_URI) para o que depende do servidor.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mockRepo executa fn com um repositório de T sobre um servidor simulado. O t passado a fn é
+// o do subteste do mtest (use-o, e não o do teste externo, para Fatal/Error).
+func mockRepo[T any](t *testing.T, opts []Option, fn func(t *testing.T, mt *mtest.T, r *Repository[T])) {
+	t.Helper()
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("mock", func(mt *mtest.T) {
+		fn(mt.T, mt, New[T](mt.DB, "items", opts...))
+	})
+}
+
+// cursorReply monta a resposta de um comando que retorna um cursor com docs (find, aggregate).
+func cursorReply(mt *mtest.T, docs ...bson.D) bson.D {
+	return mtest.CreateCursorResponse(0, mt.DB.Name()+".items", mtest.FirstBatch, docs...)
+}
+
+// okReply monta uma resposta de sucesso com os campos extras informados (ex.: n, nModified).
+func okReply(fields ...bson.E) bson.D {
+	return append(bson.D{{Key: "ok", Value: 1}}, fields...)
+}
+
+// sentCommand retorna o próximo comando enviado ao servidor simulado ainda não lido, na
+// ordem de envio (cada chamada consome um comando).
+func sentCommand(t *testing.T, mt *mtest.T) bson.Raw {
+	t.Helper()
+	ev := mt.GetStartedEvent()
+	if ev == nil {
+		t.Fatal("nenhum comando enviado")
+	}
+	return ev.Command
+}
+
+// integrationDB conecta ao servidor de MONGODB_URI e retorna um banco temporário, removido
+// ao fim do teste. Sem MONGODB_URI, o teste é pulado.
+func integrationDB(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI não definido: teste de integração pulado")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := client.Database(fmt.Sprintf("monger_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = db.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+	return db
+}
@@ -23,14 +23,18 @@ package monger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/sync/errgroup"
 )
 
 // Aliases para facilitar o uso interno e externo
@@ -41,10 +45,34 @@ type D = bson.D
 // Útil para "patch structs" (campos ponteiro) em updates parciais, inclusive com valores zerados (0, "", false).
 func Value[T any](v T) *T { return &v }
 
-// PagedResult encapsula os dados retornados e o total para paginação
+// PagedResult encapsula os dados retornados e o total para paginação.
+// Os metadados de página (Page ... HasPrev) são preenchidos por FindPage.
 type PagedResult[T any] struct {
 	Data  []T   `json:"data"`
 	Total int64 `json:"total"`
+
+	Page       int64 `json:"page,omitempty"`       // página atual (1-based)
+	PageSize   int64 `json:"pageSize,omitempty"`   // documentos por página
+	TotalPages int64 `json:"totalPages,omitempty"` // ceil(Total/PageSize); -1 se Total for desconhecido
+	HasNext    bool  `json:"hasNext,omitempty"`
+	HasPrev    bool  `json:"hasPrev,omitempty"`
+}
+
+// UpdateResult expõe as contagens retornadas pelo servidor em um update.
+// A diferença Matched - Modified é a quantidade de documentos que já estavam no estado desejado.
+type UpdateResult struct {
+	Matched  int64 `json:"matched"`  // documentos que satisfizeram o filtro
+	Modified int64 `json:"modified"` // documentos efetivamente alterados
+	Upserted int64 `json:"upserted"` // documentos inseridos por upsert
+}
+
+// newUpdateResult converte o resultado do driver em UpdateResult.
+func newUpdateResult(res *mongo.UpdateResult) *UpdateResult {
+	return &UpdateResult{
+		Matched:  res.MatchedCount,
+		Modified: res.ModifiedCount,
+		Upserted: res.UpsertedCount,
+	}
 }
 
 // --- FILTER BUILDER ---
@@ -69,7 +97,7 @@ func (b *FilterBuilder) Ne(field string, val any) *FilterBuilder { return b.NotE
 
 // NotEqual adiciona um comparador de diferença: {field: {$ne: val}}
 func (b *FilterBuilder) NotEqual(field string, val any) *FilterBuilder {
-	b.f[field] = M{"$ne": val}
+	b.mergeOp(field, "$ne", val)
 	return b
 }
 
@@ -78,7 +106,7 @@ func (b *FilterBuilder) Gt(field string, val any) *FilterBuilder { return b.Grea
 
 // GreaterThan adiciona um comparador maior que: {field: {$gt: val}}
 func (b *FilterBuilder) GreaterThan(field string, val any) *FilterBuilder {
-	b.f[field] = M{"$gt": val}
+	b.mergeOp(field, "$gt", val)
 	return b
 }
 
@@ -89,7 +117,7 @@ func (b *FilterBuilder) Gte(field string, val any) *FilterBuilder {
 
 // GreaterThanOrEqual adiciona um comparador maior ou igual: {field: {$gte: val}}
 func (b *FilterBuilder) GreaterThanOrEqual(field string, val any) *FilterBuilder {
-	b.f[field] = M{"$gte": val}
+	b.mergeOp(field, "$gte", val)
 	return b
 }
 
@@ -98,7 +126,7 @@ func (b *FilterBuilder) Lt(field string, val any) *FilterBuilder { return b.Less
 
 // LessThan adiciona um comparador menor que: {field: {$lt: val}}
 func (b *FilterBuilder) LessThan(field string, val any) *FilterBuilder {
-	b.f[field] = M{"$lt": val}
+	b.mergeOp(field, "$lt", val)
 	return b
 }
 
@@ -109,7 +137,7 @@ func (b *FilterBuilder) Lte(field string, val any) *FilterBuilder {
 
 // LessThanOrEqual adiciona um comparador menor ou igual: {field: {$lte: val}}
 func (b *FilterBuilder) LessThanOrEqual(field string, val any) *FilterBuilder {
-	b.f[field] = M{"$lte": val}
+	b.mergeOp(field, "$lte", val)
 	return b
 }
 
@@ -118,26 +146,345 @@ func (b *FilterBuilder) In(field string, vals any) *FilterBuilder { return b.InV
 
 // InValues adiciona um comparador "IN": {field: {$in: vals}}
 func (b *FilterBuilder) InValues(field string, vals any) *FilterBuilder {
-	b.f[field] = M{"$in": vals}
+	b.mergeOp(field, "$in", vals)
 	return b
 }
 
-// Operadores Lógicos (And / Or)
-func (b *FilterBuilder) And(builders ...*FilterBuilder) *FilterBuilder {
-	filters := []M{}
-	for _, sub := range builders {
-		filters = append(filters, sub.Build())
+// Nin é um alias curto para NotInValues.
+func (b *FilterBuilder) Nin(field string, vals any) *FilterBuilder { return b.NotInValues(field, vals) }
+
+// NotInValues adiciona um comparador "NOT IN": {field: {$nin: vals}}.
+// vals aceita os mesmos valores que InValues; nil ou um slice vazio geram $nin: [] (que
+// não exclui nenhum documento).
+func (b *FilterBuilder) NotInValues(field string, vals any) *FilterBuilder {
+	b.mergeOp(field, "$nin", arrayOrEmpty(vals))
+	return b
+}
+
+// All filtra arrays que contêm todos os valores informados: {field: {$all: vals}}.
+// Diferente de In (qualquer um dos valores), exige todos. vals pode ser um slice tipado
+// (ex.: []string), gravado diretamente como array BSON.
+//
+// Exemplo de uso:
+//
+//	// posts com as tags "go" e "db"
+//	f := monger.Filter().All("tags", []string{"go", "db"})
+func (b *FilterBuilder) All(field string, vals any) *FilterBuilder {
+	b.mergeOp(field, "$all", arrayOrEmpty(vals))
+	return b
+}
+
+// arrayOrEmpty troca nil (inclusive slices nil tipados, que o driver gravaria como null)
+// por um array vazio.
+func arrayOrEmpty(vals any) any {
+	if vals == nil {
+		return []any{}
+	}
+	if rv := reflect.ValueOf(vals); rv.Kind() == reflect.Slice && rv.IsNil() {
+		return []any{}
+	}
+	return vals
+}
+
+// Exists filtra pela presença (exists = true) ou ausência (exists = false) do campo:
+// {field: {$exists: exists}}. Se o campo já tiver operadores no filtro (ex.: Gt), o $exists é
+// acrescentado ao mesmo sub-documento em vez de substituí-los.
+//
+// Exemplo de uso:
+//
+//	// documentos que já têm o campo novo
+//	f := monger.Filter().Exists("preferences", true)
+func (b *FilterBuilder) Exists(field string, exists bool) *FilterBuilder {
+	b.mergeOp(field, "$exists", exists)
+	return b
+}
+
+// mergeOp acrescenta o operador op ao sub-documento de operadores do campo, preservando os
+// operadores já definidos (ex.: Gt + Lt no mesmo campo). Uma igualdade simples já presente
+// ({field: val}, de Equal) é mantida como {$eq: val} (uma regex, como {$regex: val}), para que
+// nenhuma condição se perca. Equal, por sua vez, sempre substitui a entrada inteira.
+func (b *FilterBuilder) mergeOp(field, op string, val any) {
+	merged := M{}
+	if cur, exists := b.f[field]; exists {
+		switch c := cur.(type) {
+		case M:
+			if isOperatorDocument(c) {
+				for k, v := range c {
+					merged[k] = v
+				}
+			} else {
+				merged["$eq"] = c
+			}
+		case primitive.Regex:
+			merged["$regex"] = c
+		default:
+			merged["$eq"] = c
+		}
+	}
+	merged[op] = val
+	b.f[field] = merged
+}
+
+// Regex filtra strings que casam com a expressão regular pattern (sintaxe PCRE do MongoDB).
+// opts são as opções do $regex concatenadas (ex.: "i" para ignorar maiúsculas/minúsculas,
+// "m" para multilinha). O valor é gravado como primitive.Regex, o tipo regex nativo do BSON,
+// em {field: {$regex: /pattern/opts}}, somando-se aos outros operadores do campo (ex.:
+// Regex + Ne).
+//
+// Atenção: pattern é usado como está; para texto digitado pelo usuário, prefira Contains,
+// StartsWith ou EndsWith, que escapam os caracteres especiais.
+//
+// Exemplo de uso:
+//
+//	f := monger.Filter().Regex("email", "@gmail\\.com$", "i")
+func (b *FilterBuilder) Regex(field, pattern string, opts ...string) *FilterBuilder {
+	b.mergeOp(field, "$regex", primitive.Regex{Pattern: pattern, Options: strings.Join(opts, "")})
+	return b
+}
+
+// StartsWith filtra strings que começam com prefix (escapado com regexp.QuoteMeta).
+// Sem a opção "i", buscas por prefixo podem usar índice.
+func (b *FilterBuilder) StartsWith(field, prefix string, opts ...string) *FilterBuilder {
+	return b.Regex(field, "^"+regexp.QuoteMeta(prefix), opts...)
+}
+
+// EndsWith filtra strings que terminam com suffix (escapado com regexp.QuoteMeta).
+func (b *FilterBuilder) EndsWith(field, suffix string, opts ...string) *FilterBuilder {
+	return b.Regex(field, regexp.QuoteMeta(suffix)+"$", opts...)
+}
+
+// Contains filtra strings que contêm sub (escapado com regexp.QuoteMeta).
+//
+// Exemplo de uso:
+//
+//	// busca parcial por nome, ignorando maiúsculas/minúsculas
+//	f := monger.Filter().Contains("name", input, "i")
+func (b *FilterBuilder) Contains(field, sub string, opts ...string) *FilterBuilder {
+	return b.Regex(field, regexp.QuoteMeta(sub), opts...)
+}
+
+// Between filtra valores no intervalo fechado [lo, hi]: {field: {$gte: lo, $lte: hi}}.
+//
+// Exemplo de uso:
+//
+//	f := monger.Filter().Between("age", 18, 65)
+func (b *FilterBuilder) Between(field string, lo, hi any) *FilterBuilder {
+	b.mergeOp(field, "$gte", lo)
+	b.mergeOp(field, "$lte", hi)
+	return b
+}
+
+// DateBetween filtra datas no intervalo [from, to) ou, com inclusiveEnd, [from, to]:
+// {field: {$gte: from, $lt: to}} ou {field: {$gte: from, $lte: to}}.
+//
+// As datas são convertidas para UTC (datas BSON são sempre UTC): um time.Time em fuso local
+// representa o mesmo instante, apenas normalizado. Para "o dia inteiro", prefira o fim
+// exclusivo com o início do dia seguinte, em vez de 23:59:59 com inclusiveEnd.
+//
+// Exemplo de uso:
+//
+//	start := time.Date(2025, 3, 1, 0, 0, 0, 0, loc)
+//	f := monger.Filter().DateBetween("createdAt", start, start.AddDate(0, 1, 0), false) // março inteiro
+func (b *FilterBuilder) DateBetween(field string, from, to time.Time, inclusiveEnd bool) *FilterBuilder {
+	end := "$lt"
+	if inclusiveEnd {
+		end = "$lte"
+	}
+	b.mergeOp(field, "$gte", from.UTC())
+	b.mergeOp(field, end, to.UTC())
+	return b
+}
+
+// NonEmpty filtra documentos em que o campo (string) existe e não é nulo nem vazio:
+// {field: {$exists: true, $nin: [null, ""]}}
+//
+// Note que {$ne: null} sozinho não exclui a string vazia, e {$ne: ""} sozinho não exclui nulos.
+func (b *FilterBuilder) NonEmpty(field string) *FilterBuilder {
+	b.mergeOp(field, "$exists", true)
+	b.mergeOp(field, "$nin", []any{nil, ""})
+	return b
+}
+
+// NonEmptyArray filtra documentos em que o campo existe, é um array e tem ao menos um elemento:
+// {field: {$exists: true, $type: "array", $not: {$size: 0}}}
+func (b *FilterBuilder) NonEmptyArray(field string) *FilterBuilder {
+	b.mergeOp(field, "$exists", true)
+	b.mergeOp(field, "$type", "array")
+	b.mergeOp(field, "$not", M{"$size": 0})
+	return b
+}
+
+// ElemMatch filtra arrays de sub-documentos em que um mesmo elemento satisfaz todas as
+// condições de sub: {field: {$elemMatch: sub}}. Um sub nil gera $elemMatch: {}. Pode ser
+// aninhado para arrays dentro de arrays.
+//
+// Exemplo de uso:
+//
+//	// pedidos com algum item de quantidade > 2 e preço < 10
+//	f := monger.Filter().ElemMatch("items", monger.Filter().Gt("quantity", 2).Lt("price", 10))
+//
+//	// arrays dentro de arrays
+//	f = monger.Filter().ElemMatch("shipments", monger.Filter().ElemMatch("packages", monger.Filter().Gt("weight", 30)))
+func (b *FilterBuilder) ElemMatch(field string, sub *FilterBuilder) *FilterBuilder {
+	cond := M{}
+	if sub != nil {
+		cond = sub.Build()
+	}
+	b.mergeOp(field, "$elemMatch", cond)
+	return b
+}
+
+// AllElemMatch filtra documentos em que o array field tem, para cada sub-filtro, ao menos um
+// elemento que o satisfaz: {field: {$all: [{$elemMatch: sub1}, {$elemMatch: sub2}, ...]}}.
+// Os elementos que satisfazem cada sub-filtro podem ser diferentes (diferente de um único
+// $elemMatch, que exige um mesmo elemento satisfazendo tudo).
+//
+// Exemplo de uso:
+//
+//	// alunos com nota > 90 em matemática e > 80 em ciências
+//	f := monger.Filter().AllElemMatch("grades",
+//	    monger.Filter().Eq("subject", "math").Gt("score", 90),
+//	    monger.Filter().Eq("subject", "science").Gt("score", 80),
+//	)
+func (b *FilterBuilder) AllElemMatch(field string, subs ...*FilterBuilder) *FilterBuilder {
+	all := make([]M, 0, len(subs))
+	for _, sub := range subs {
+		if sub == nil {
+			continue
+		}
+		all = append(all, M{"$elemMatch": sub.Build()})
 	}
-	b.f["$and"] = filters
+	if len(all) == 0 {
+		return b
+	}
+	b.mergeOp(field, "$all", all)
 	return b
 }
 
+// Operadores Lógicos (And / Or / Nor / Not)
+// And adiciona {$and: [...]}. Chamadas repetidas acrescentam os sub-filtros à mesma lista.
+func (b *FilterBuilder) And(builders ...*FilterBuilder) *FilterBuilder {
+	return b.logical("$and", builders)
+}
+
+// Or adiciona {$or: [...]}. Chamadas repetidas acrescentam os sub-filtros à mesma lista.
 func (b *FilterBuilder) Or(builders ...*FilterBuilder) *FilterBuilder {
-	filters := []M{}
+	return b.logical("$or", builders)
+}
+
+// Nor adiciona {$nor: [...]}: documentos que não satisfazem nenhum dos sub-filtros.
+// É a negação de um Or inteiro, válida no nível do documento.
+func (b *FilterBuilder) Nor(builders ...*FilterBuilder) *FilterBuilder {
+	return b.logical("$nor", builders)
+}
+
+// logical acrescenta os sub-filtros à lista do operador op ($and, $or ou $nor), preservando
+// os itens já existentes (como RawField).
+func (b *FilterBuilder) logical(op string, builders []*FilterBuilder) *FilterBuilder {
+	filters := make([]M, 0, len(builders))
 	for _, sub := range builders {
 		filters = append(filters, sub.Build())
 	}
-	b.f["$or"] = filters
+	switch cur := b.f[op].(type) {
+	case nil:
+		b.f[op] = filters
+	case []M:
+		b.f[op] = append(append([]M{}, cur...), filters...)
+	default:
+		b.RawField(op, filters) // lista vinda de Raw ([]any, bson.A)
+	}
+	return b
+}
+
+// Not nega a condição de um único campo: usa a condição de field montada em sub e grava
+// {field: {$not: condição}}. No MongoDB, $not só se aplica a operadores de um campo (não a
+// um filtro inteiro); para negar várias condições/campos, use Nor.
+//
+// Atenção: assim como no MongoDB, Not também retorna documentos em que o campo não existe.
+// Se sub não tiver condição para field, nada é adicionado.
+//
+// Exemplo de uso:
+//
+//	// nomes que não começam com "test"
+//	f := monger.Filter().Not("name", monger.Filter().StartsWith("name", "test"))
+func (b *FilterBuilder) Not(field string, sub *FilterBuilder) *FilterBuilder {
+	if sub == nil {
+		return b
+	}
+	cond, ok := sub.Build()[field]
+	if !ok {
+		return b
+	}
+	switch c := cond.(type) {
+	case M:
+		if !isOperatorDocument(c) {
+			cond = M{"$eq": c}
+		}
+	case primitive.Regex:
+	default:
+		cond = M{"$eq": c}
+	}
+	b.mergeOp(field, "$not", cond)
+	return b
+}
+
+// Raw mescla um documento de filtro arbitrário ao builder, para operadores que ainda não têm
+// método próprio (ex.: $jsonSchema). Para cada chave:
+//   - se o campo já tiver operadores e o novo valor também for um documento de operadores,
+//     os dois são mesclados (em caso de operador repetido, vale o novo);
+//   - $and/$or/$nor já existentes recebem os novos sub-filtros no fim da lista;
+//   - $expr já existente é combinado com o novo via $and (como em Expr);
+//   - nos demais casos, o novo valor substitui o anterior.
+//
+// Exemplo de uso:
+//
+//	f := monger.Filter().Gt("age", 18).Raw(monger.M{"$jsonSchema": schema})
+func (b *FilterBuilder) Raw(m M) *FilterBuilder {
+	for field, val := range m {
+		b.RawField(field, val)
+	}
+	return b
+}
+
+// RawField mescla uma condição arbitrária em um único campo, com as mesmas regras de Raw.
+//
+// Exemplo de uso:
+//
+//	f := monger.Filter().Gt("age", 18).RawField("age", monger.M{"$mod": []int{2, 0}}) // {age: {$gt: 18, $mod: [2, 0]}}
+func (b *FilterBuilder) RawField(field string, expr any) *FilterBuilder {
+	cur, exists := b.f[field]
+	if !exists {
+		b.f[field] = expr
+		return b
+	}
+
+	switch field {
+	case "$and", "$or", "$nor":
+		if items := listElements(cur); items != nil {
+			if more := listElements(expr); more != nil {
+				b.f[field] = append(append([]any{}, items...), more...)
+				return b
+			}
+		}
+	case "$expr":
+		b.f[field] = M{"$and": []any{cur, expr}}
+		return b
+	}
+
+	curOps, ok1 := cur.(M)
+	newOps, ok2 := expr.(M)
+	if ok1 && ok2 && isOperatorDocument(curOps) && isOperatorDocument(newOps) {
+		merged := make(M, len(curOps)+len(newOps))
+		for k, v := range curOps {
+			merged[k] = v
+		}
+		for k, v := range newOps {
+			merged[k] = v
+		}
+		b.f[field] = merged
+		return b
+	}
+	b.f[field] = expr
 	return b
 }
 
@@ -167,17 +514,155 @@ func Exclude(fields ...string) *ProjectBuilder {
 	return &ProjectBuilder{p: m}
 }
 
+// FirstMatching projeta em field apenas o primeiro elemento do array field que satisfaz cond,
+// usando {$arrayElemAt: [{$filter: ...}, 0]}. Em cond, o elemento é referenciado como "$$this".
+// Sem elemento correspondente, o campo é omitido do resultado.
+//
+// Diferente da projeção posicional ("items.$"), não depende do filtro da consulta ter uma
+// condição sobre o mesmo array, permite qualquer expressão em cond e pode ser usada em mais de
+// um array. Requer MongoDB 4.4+ (expressões de agregação em projeções de find) e não pode ser
+// combinada com projeções de exclusão (Exclude).
+//
+// Exemplo de uso:
+//
+//	// apenas o primeiro endereço principal
+//	p := monger.Select("name").FirstMatching("addresses", monger.M{"$eq": []any{"$$this.primary", true}})
+func (b *ProjectBuilder) FirstMatching(field string, cond M) *ProjectBuilder {
+	b.p[field] = M{"$arrayElemAt": []any{
+		M{"$filter": M{"input": fieldRef(field), "cond": cond}},
+		0,
+	}}
+	return b
+}
+
+// Slice projeta apenas parte do array field: os n primeiros elementos (n > 0) ou os |n|
+// últimos (n < 0), com {field: {$slice: n}}. Os demais campos seguem a projeção (com Select()
+// sem campos, o documento vem inteiro, com o array cortado).
+//
+// Exemplo de uso:
+//
+//	// post com os 5 comentários mais recentes (gravados com $push no fim do array)
+//	p := monger.Select("title").Slice("comments", -5)
+func (b *ProjectBuilder) Slice(field string, n int) *ProjectBuilder {
+	b.p[field] = M{"$slice": n}
+	return b
+}
+
+// SliceRange projeta limit elementos do array field a partir da posição skip (negativa conta
+// do fim), com {field: {$slice: [skip, limit]}}. Útil para paginar arrays embutidos.
+//
+// Exemplo de uso:
+//
+//	p := monger.Select("title").SliceRange("comments", 20, 10) // comentários 21 a 30
+func (b *ProjectBuilder) SliceRange(field string, skip, limit int) *ProjectBuilder {
+	b.p[field] = M{"$slice": []int{skip, limit}}
+	return b
+}
+
+// ElemMatch projeta apenas o primeiro elemento do array field que satisfaz sub, com
+// {field: {$elemMatch: ...}}. As condições de sub são relativas ao elemento (ex.: Eq("status",
+// "open") para arrays de subdocumentos). Sem elemento correspondente, o campo é omitido.
+//
+// Exemplo de uso:
+//
+//	p := monger.Select("name").ElemMatch("orders", monger.Filter().Eq("status", "open"))
+func (b *ProjectBuilder) ElemMatch(field string, sub *FilterBuilder) *ProjectBuilder {
+	cond := M{}
+	if sub != nil {
+		cond = sub.Build()
+	}
+	b.p[field] = M{"$elemMatch": cond}
+	return b
+}
+
 func (b *ProjectBuilder) Build() M {
 	return b.p
 }
 
+// --- SORT BUILDER ---
+// Monta ordenações (bson.D) na ordem em que os campos são adicionados, sem escrever
+// D{{Key: ..., Value: ...}} à mão
+type SortBuilder struct {
+	s D
+}
+
+// Sort cria um SortBuilder vazio. A ordem das chamadas define a prioridade da ordenação.
+//
+// Exemplo de uso:
+//
+//	sort := monger.Sort().Desc("createdAt").Asc("name").Build()
+//	// D{{createdAt, -1}, {name, 1}}
+//	res, err := posts.FindPaged(ctx, nil, nil, 0, 20, sort)
+func Sort() *SortBuilder {
+	return &SortBuilder{s: D{}}
+}
+
+// Asc adiciona os campos em ordem crescente (1).
+func (b *SortBuilder) Asc(fields ...string) *SortBuilder { return b.add(1, fields) }
+
+// Desc adiciona os campos em ordem decrescente (-1).
+func (b *SortBuilder) Desc(fields ...string) *SortBuilder { return b.add(-1, fields) }
+
+// add registra cada campo com a direção; um campo repetido mantém a posição original e
+// passa a usar a nova direção (o servidor rejeita chaves repetidas na ordenação).
+func (b *SortBuilder) add(dir int, fields []string) *SortBuilder {
+	for _, f := range fields {
+		replaced := false
+		for i := range b.s {
+			if b.s[i].Key == f {
+				b.s[i].Value = dir
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			b.s = append(b.s, bson.E{Key: f, Value: dir})
+		}
+	}
+	return b
+}
+
+// Build retorna a ordenação (nil se nenhum campo foi adicionado, o que usa a ordenação padrão
+// do repositório nos métodos que aceitam sort).
+func (b *SortBuilder) Build() D {
+	if len(b.s) == 0 {
+		return nil
+	}
+	return b.s
+}
+
 // --- REPOSITORY ---
 type Repository[T any] struct {
-	coll *mongo.Collection
+	coll    *mongo.Collection
+	cfg     config
+	schema  *schemaState // WithSchemaValidation
+	indexes *indexCache  // WithExplainWarnings
 }
 
-func New[T any](db *mongo.Database, collectionName string) *Repository[T] {
-	return &Repository[T]{coll: db.Collection(collectionName)}
+// New cria um Repository para a coleção informada.
+// Opções (WithAllowDiskUse, ...) ajustam o comportamento do repositório.
+func New[T any](db *mongo.Database, collectionName string, opts ...Option) *Repository[T] {
+	r := &Repository[T]{}
+	for _, opt := range opts {
+		opt(&r.cfg)
+	}
+	r.coll = db.Collection(collectionName, r.cfg.collectionOptions())
+	if r.cfg.schemaValidation {
+		r.schema = &schemaState{}
+	}
+	if r.cfg.explainWarnings {
+		r.indexes = &indexCache{warned: map[string]bool{}}
+	}
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	r.cfg.immutableFields = append(r.cfg.immutableFields, immutableTagFields(model)...)
+	// WithTimestamps só mexe nos campos que o model declara
+	if r.cfg.createdField != "" && !declaresField(model, r.cfg.createdField) {
+		r.cfg.createdField = ""
+	}
+	if r.cfg.updatedField != "" && !declaresField(model, r.cfg.updatedField) {
+		r.cfg.updatedField = ""
+	}
+	return r
 }
 
 // getOpts é um auxiliar interno para preparar filtros e projeções
@@ -193,91 +678,300 @@ func (r *Repository[T]) getOpts(f *FilterBuilder, p *ProjectBuilder) (M, *option
 	return filter, opts
 }
 
-// InsertOne insere um documento e retorna o ID hex
-func (r *Repository[T]) InsertOne(ctx context.Context, model *T) (string, error) {
-	res, err := r.coll.InsertOne(ctx, model)
+// scopeFilter aplica ao filtro de uma leitura as restrições obrigatórias do repositório
+// (WithSoftDelete, WithRowSecurity). Toda leitura deve passar por aqui antes de ir ao servidor.
+func (r *Repository[T]) scopeFilter(ctx context.Context, filter M) (M, error) {
+	if includesDeleted(ctx) {
+		return r.securityFilter(ctx, filter)
+	}
+	return r.securityFilter(ctx, andFilters(filter, r.softDeleteFilter()))
+}
+
+// securityFilter aplica ao filtro apenas a restrição de WithRowSecurity (sem ocultar
+// documentos excluídos), para leituras que precisam enxergar exclusões lógicas.
+func (r *Repository[T]) securityFilter(ctx context.Context, filter M) (M, error) {
+	if r.cfg.rowSecurity == nil {
+		return filter, nil
+	}
+	sec, err := r.cfg.rowSecurity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("filtro de segurança: %w", err)
+	}
+	if sec == nil {
+		return filter, nil
+	}
+	return andFilters(filter, sec.Build()), nil
+}
+
+// readFilter monta o filtro de uma leitura (nil vira filtro vazio) já com as restrições do repositório.
+func (r *Repository[T]) readFilter(ctx context.Context, f *FilterBuilder) (M, error) {
+	filter := M{}
+	if f != nil {
+		filter = f.Build()
+	}
+	return r.scopeFilter(ctx, filter)
+}
+
+// andFilters combina filtros com $and, ignorando filtros vazios.
+// Se sobrar apenas um filtro, ele é retornado sem o $and.
+func andFilters(filters ...M) M {
+	parts := []M{}
+	for _, f := range filters {
+		if len(f) > 0 {
+			parts = append(parts, f)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return M{}
+	case 1:
+		return parts[0]
+	}
+	return M{"$and": parts}
+}
+
+// InsertOne insere um documento e retorna o ID (hex do ObjectID, ou conforme WithIDCodec)
+func (r *Repository[T]) InsertOne(ctx context.Context, model *T) (id string, err error) {
+	ctx, span := r.startSpan(ctx, "InsertOne")
+	defer func() { span.end(err, 1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if err := r.ensureSchema(ctx); err != nil {
+		return "", err
+	}
+	doc, err := r.insertDocument(ctx, model)
 	if err != nil {
 		return "", err
 	}
-	oid, ok := res.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return "", fmt.Errorf("erro ao converter ID inserido")
+	r.logOp("InsertOne", func() M { return M{"document": doc} })
+	res, err := r.coll.InsertOne(ctx, doc)
+	if err != nil {
+		return "", writeError(err)
 	}
-	return oid.Hex(), nil
+	return r.cfg.formatID(res.InsertedID)
 }
 
-// InsertOneAndUpdate realiza um upsert: se o documento já existir (baseado no filtro), atualiza apenas os campos diferentes;
-// se não existir, insere o documento completo.
+// InsertManyOption configura uma chamada de InsertMany.
+type InsertManyOption func(*options.InsertManyOptions)
+
+// InsertUnordered insere o lote sem ordem (ordered: false): um documento inválido (ex.: chave
+// duplicada) não interrompe a inserção dos demais.
+func InsertUnordered() InsertManyOption {
+	return func(o *options.InsertManyOptions) { o.SetOrdered(false) }
+}
+
+// InsertMany insere os documentos em uma única chamada ao servidor e retorna os IDs na mesma
+// ordem de models. IDs ObjectID vêm em hex; IDs string ou inteiros (definidos no model) vêm
+// como texto (veja WithIDCodec).
 //
-// Parâmetros:
-//   - ctx: contexto da operação
-//   - filter: filtro para identificar o documento (obrigatório, use FilterBuilder com um campo único como email, cpf, etc.)
-//   - model: documento a ser inserido ou usado para atualização
+// Por padrão o lote é ordenado: a primeira falha interrompe a inserção dos seguintes. Com
+// InsertUnordered, os demais documentos continuam sendo inseridos. Se alguns documentos
+// falharem, os IDs são retornados junto com um erro que satisfaz errors.Is(err, ErrPartialWrite)
+// (e também ErrDuplicateKey, se alguma falha foi de chave duplicada); as posições não
+// inseridas ficam com "".
 //
-// Retorna o ID do documento (inserido ou existente) e um booleano indicando se foi uma inserção (true) ou atualização (false).
+// Se algum _id não for ObjectID nem string, os documentos continuam inseridos, mas é retornado
+// um erro indicando a posição (use um tipo de _id conversível para string).
 //
-// Nota: Para inserir novos documentos, use InsertOne. Para atualizar por _id, use UpdateByID.
-// Use InsertOneAndUpdate apenas para upsert por campos únicos (ex: email, cpf, sku).
-func (r *Repository[T]) InsertOneAndUpdate(ctx context.Context, filter *FilterBuilder, model *T) (string, bool, error) {
-	if filter == nil {
-		return "", false, fmt.Errorf("filter é obrigatório")
+// Exemplo de uso:
+//
+//	ids, err := users.InsertMany(ctx, batch, monger.InsertUnordered())
+//	if errors.Is(err, monger.ErrPartialWrite) {
+//	    // ids[i] == "" para os documentos que falharam
+//	}
+func (r *Repository[T]) InsertMany(ctx context.Context, models []T, opts ...InsertManyOption) (ids []string, err error) {
+	ctx, span := r.startSpan(ctx, "InsertMany")
+	defer func() { span.end(err, int64(len(ids))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if len(models) == 0 {
+		return []string{}, nil
 	}
-	if model == nil {
-		return "", false, fmt.Errorf("model não pode ser nil")
+	if err := r.ensureSchema(ctx); err != nil {
+		return nil, err
 	}
 
-	// Monta o filtro
-	f := filter.Build()
-	if len(f) == 0 {
-		return "", false, fmt.Errorf("filter não pode ser vazio")
+	docs := make([]any, len(models))
+	for i := range models {
+		doc, err := r.insertDocument(ctx, &models[i])
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
 	}
 
-	// Constrói o documento de update
-	doc, err := buildPartialUpdate(model)
-	if err != nil {
-		return "", false, err
+	o := options.InsertMany()
+	for _, opt := range opts {
+		opt(o)
 	}
-
-	opts := options.Update().SetUpsert(true)
-	res, err := r.coll.UpdateOne(ctx, f, M{"$set": doc}, opts)
-	if err != nil {
-		return "", false, err
+	r.logOp("InsertMany", func() M { return M{"documents": docs} })
+	res, err := r.coll.InsertMany(ctx, docs, o)
+	if res == nil {
+		return nil, err
 	}
 
-	// Determina o ID retornado
-	var id string
-	isInsert := res.UpsertedCount > 0
-
-	if isInsert {
-		// Documento foi inserido
-		if oid, ok := res.UpsertedID.(primitive.ObjectID); ok {
-			id = oid.Hex()
-		} else {
-			return "", false, fmt.Errorf("erro ao converter ID do upsert")
+	failed := map[int]bool{}
+	var bwe mongo.BulkWriteException
+	if err != nil {
+		if !errors.As(err, &bwe) || bwe.WriteConcernError != nil {
+			return nil, err
 		}
-	} else {
-		// Documento foi atualizado - busca o ID existente
-		if oid, ok := f["_id"].(primitive.ObjectID); ok {
-			id = oid.Hex()
-		} else {
-			// Busca o documento para obter o ID
-			var existing M
-			err := r.coll.FindOne(ctx, f, options.FindOne().SetProjection(M{"_id": 1})).Decode(&existing)
-			if err != nil {
-				return "", false, fmt.Errorf("erro ao buscar ID do documento atualizado: %w", err)
-			}
-			if oid, ok := existing["_id"].(primitive.ObjectID); ok {
-				id = oid.Hex()
+		ordered := o.Ordered == nil || *o.Ordered
+		for _, we := range bwe.WriteErrors {
+			if ordered {
+				for i := we.Index; i < len(models); i++ {
+					failed[i] = true
+				}
+				break
 			}
+			failed[we.Index] = true
 		}
 	}
 
-	return id, isInsert, nil
-}
+	ids = make([]string, len(res.InsertedIDs))
+	for i, id := range res.InsertedIDs {
+		if failed[i] {
+			continue
+		}
+		s, ferr := r.cfg.formatID(id)
+		if ferr != nil {
+			return ids, fmt.Errorf("documentos inseridos, mas o _id na posição %d não pôde ser convertido: %w", i, ferr)
+		}
+		ids[i] = s
+	}
+	if len(failed) > 0 {
+		return ids, writeError(fmt.Errorf("%w: %d de %d documentos não foram inseridos: %w", ErrPartialWrite, len(failed), len(models), err))
+	}
+	return ids, nil
+}
+
+// InsertWithID insere o documento com um _id escolhido pelo chamador (ex.: um hash
+// determinístico ou um código natural). Se já existir um documento com esse _id, retorna
+// ErrDuplicateKey. Se o model tiver o próprio _id preenchido, ele deve ser igual a id.
+//
+// Exemplo de uso:
+//
+//	err := events.InsertWithID(ctx, sha256Hex(payload), &event)
+//	if errors.Is(err, monger.ErrDuplicateKey) {
+//	    // evento já registrado
+//	}
+func (r *Repository[T]) InsertWithID(ctx context.Context, id any, model *T) (err error) {
+	ctx, span := r.startSpan(ctx, "InsertWithID")
+	defer func() { span.end(err, 1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if id == nil {
+		return fmt.Errorf("id não pode ser nil")
+	}
+	if model == nil {
+		return fmt.Errorf("model não pode ser nil")
+	}
+	if err := r.ensureSchema(ctx); err != nil {
+		return err
+	}
+
+	prepared, err := r.insertDocument(ctx, model)
+	if err != nil {
+		return err
+	}
+	doc, ok := prepared.(M)
+	if !ok {
+		if doc, err = toDocument(prepared); err != nil {
+			return err
+		}
+	}
+	if current, ok := doc["_id"]; ok && !reflect.DeepEqual(current, id) {
+		return fmt.Errorf("_id do model (%v) diferente do id informado (%v)", current, id)
+	}
+	doc["_id"] = id
+
+	r.logOp("InsertWithID", func() M { return M{"document": doc} })
+	if _, err := r.coll.InsertOne(ctx, doc); err != nil {
+		return writeError(err)
+	}
+	return nil
+}
+
+// InsertOneAndUpdate realiza um upsert: se o documento já existir (baseado no filtro), atualiza apenas os campos diferentes;
+// se não existir, insere o documento completo.
+//
+// Parâmetros:
+//   - ctx: contexto da operação
+//   - filter: filtro para identificar o documento (obrigatório, use FilterBuilder com um campo único como email, cpf, etc.)
+//   - model: documento a ser inserido ou usado para atualização
+//
+// Retorna o ID do documento (inserido ou existente) e um booleano indicando se foi uma inserção (true) ou atualização (false).
+//
+// Nota: Para inserir novos documentos, use InsertOne. Para atualizar por _id, use UpdateByID.
+// Use InsertOneAndUpdate apenas para upsert por campos únicos (ex: email, cpf, sku).
+func (r *Repository[T]) InsertOneAndUpdate(ctx context.Context, filter *FilterBuilder, model *T) (id string, isInsert bool, err error) {
+	ctx, span := r.startSpan(ctx, "InsertOneAndUpdate")
+	defer func() { span.end(err, 1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if err := r.ensureSchema(ctx); err != nil {
+		return "", false, err
+	}
+	if filter == nil {
+		return "", false, fmt.Errorf("filter é obrigatório")
+	}
+	if model == nil {
+		return "", false, fmt.Errorf("model não pode ser nil")
+	}
+
+	// Monta o filtro
+	f := filter.Build()
+	if len(f) == 0 {
+		return "", false, fmt.Errorf("filter não pode ser vazio")
+	}
+
+	// Constrói o documento de update
+	if err := beforeUpdate(ctx, model); err != nil {
+		return "", false, err
+	}
+	doc, err := r.cfg.buildPartialUpdate(model)
+	if err != nil {
+		return "", false, err
+	}
+
+	set, onInsert := r.splitImmutable(doc)
+	opts := options.Update().SetUpsert(true)
+	res, err := r.coll.UpdateOne(ctx, f, r.touchUpsert(set, onInsert), opts)
+	if err != nil {
+		return "", false, writeError(err)
+	}
+
+	// Determina o ID retornado
+	isInsert = res.UpsertedCount > 0
+
+	if isInsert {
+		// Documento foi inserido
+		if id, err = r.cfg.formatID(res.UpsertedID); err != nil {
+			return "", false, err
+		}
+	} else {
+		// Documento foi atualizado - busca o ID existente
+		if current, ok := f["_id"]; ok {
+			id = r.cfg.idString(current)
+		} else {
+			// Busca o documento para obter o ID
+			var existing M
+			err := r.coll.FindOne(ctx, f, options.FindOne().SetProjection(M{"_id": 1})).Decode(&existing)
+			if err != nil {
+				return "", false, fmt.Errorf("erro ao buscar ID do documento atualizado: %w", err)
+			}
+			id = r.cfg.idString(existing["_id"])
+		}
+	}
+
+	return id, isInsert, nil
+}
 
 // Find busca um único documento com filtro e projeção.
 // Ideal para buscas por campos únicos como _id, cpf, email, etc.
 // O filtro é obrigatório para evitar retornar documentos aleatórios.
+// Sem resultado, retorna um erro que satisfaz errors.Is(err, ErrNotFound) (e, por
+// compatibilidade, também errors.Is(err, mongo.ErrNoDocuments)).
 //
 // Exemplo de uso:
 //
@@ -289,26 +983,198 @@ func (r *Repository[T]) InsertOneAndUpdate(ctx context.Context, filter *FilterBu
 //
 //	// Buscar por email com projeção
 //	user, err := users.Find(ctx, monger.Filter().Eq("email", "ana@email.com"), monger.Select("name", "email"))
-func (r *Repository[T]) Find(ctx context.Context, f *FilterBuilder, p *ProjectBuilder) (*T, error) {
+func (r *Repository[T]) Find(ctx context.Context, f *FilterBuilder, p *ProjectBuilder) (out *T, err error) {
+	ctx, span := r.startSpan(ctx, "Find")
+	defer func() { span.end(err, found(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
 	if f == nil {
 		return nil, fmt.Errorf("filtro é obrigatório para Find; use FindAll para buscar múltiplos documentos")
 	}
-	filter := f.Build()
+	filter, err := r.scopeFilter(ctx, f.Build())
+	if err != nil {
+		return nil, err
+	}
+	opts := options.FindOne()
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+	if sort := r.sortOrDefault(nil); sort != nil {
+		opts.SetSort(sort)
+		r.checkSortIndex(ctx, sort)
+	}
+	r.logOp("Find", func() M { return M{"filter": filter, "projection": opts.Projection, "sort": opts.Sort} })
+	doc, err := r.findOne(ctx, filter, opts)
+	return doc, notFound(err)
+}
+
+// FindOne busca um único documento com filtro, como Find, mas com ordenação opcional (para
+// consultas do tipo "o mais recente que satisfaz o filtro") e retornando ErrNotFound quando
+// nenhum documento satisfaz o filtro. Sem sort (nil), usa a ordenação padrão (WithDefaultSort).
+//
+// Exemplo de uso:
+//
+//	user, err := users.FindOne(ctx, monger.Filter().Eq("email", email), nil, nil)
+//	if errors.Is(err, monger.ErrNotFound) {
+//	    // não cadastrado
+//	}
+//
+//	// último pedido do cliente
+//	order, err := orders.FindOne(ctx, monger.Filter().Eq("customerId", cid), nil, monger.D{{Key: "createdAt", Value: -1}})
+func (r *Repository[T]) FindOne(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, sort D) (out *T, err error) {
+	ctx, span := r.startSpan(ctx, "FindOne")
+	defer func() { span.end(err, found(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if f == nil {
+		return nil, fmt.Errorf("filtro é obrigatório para FindOne; use FindAll para buscar múltiplos documentos")
+	}
+	filter, err := r.scopeFilter(ctx, f.Build())
+	if err != nil {
+		return nil, err
+	}
+	opts := options.FindOne()
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+	if sort = r.sortOrDefault(sort); sort != nil {
+		opts.SetSort(sort)
+		r.checkSortIndex(ctx, sort)
+	}
+	r.logOp("FindOne", func() M { return M{"filter": filter, "projection": opts.Projection, "sort": opts.Sort} })
+	doc, err := r.findOne(ctx, filter, opts)
+	return doc, notFound(err)
+}
+
+// FindByID busca um documento pelo _id (hex do ObjectID, ou conforme WithIDCodec), com
+// projeção opcional.
+// Um id inválido retorna erro que satisfaz errors.Is(err, ErrInvalidID); sem resultado, como
+// no Find, o erro satisfaz errors.Is(err, ErrNotFound).
+//
+// Exemplo de uso:
+//
+//	user, err := users.FindByID(ctx, id, monger.Select("name", "email"))
+func (r *Repository[T]) FindByID(ctx context.Context, id string, p *ProjectBuilder) (out *T, err error) {
+	ctx, span := r.startSpan(ctx, "FindByID")
+	defer func() { span.end(err, found(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	oid, err := r.cfg.parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := r.scopeFilter(ctx, M{"_id": oid})
+	if err != nil {
+		return nil, err
+	}
 	opts := options.FindOne()
 	if p != nil {
 		opts.SetProjection(p.Build())
 	}
-	var res T
-	err := r.coll.FindOne(ctx, filter, opts).Decode(&res)
+	r.logOp("FindByID", func() M { return M{"filter": filter, "projection": opts.Projection} })
+	doc, err := r.findOne(ctx, filter, opts)
+	return doc, notFound(err)
+}
+
+// FindByIDs busca, em uma única consulta ($in), os documentos com os _ids (hex) informados.
+// Ids que não existem são simplesmente omitidos do resultado; ids inválidos geram
+// erro listando quais (nenhuma consulta é feita). Sem ids, retorna um slice vazio.
+//
+// A ordem do resultado não é especificada (não segue a ordem de ids); para listas muito
+// grandes, use FindByIDsChunked.
+//
+// Exemplo de uso:
+//
+//	users, err := usersRepo.FindByIDs(ctx, []string{id1, id2, id3}, monger.Select("name"))
+func (r *Repository[T]) FindByIDs(ctx context.Context, ids []string, p *ProjectBuilder) (out []T, err error) {
+	ctx, span := r.startSpan(ctx, "FindByIDs")
+	defer func() { span.end(err, int64(len(out))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	oids, err := r.cfg.parseIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(oids) == 0 {
+		return []T{}, nil
+	}
+	filter, err := r.scopeFilter(ctx, M{"_id": M{"$in": oids}})
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find()
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+	r.logOp("FindByIDs", func() M { return M{"filter": filter, "projection": opts.Projection} })
+	cursor, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	return decodeCursor[T](ctx, cursor, r.coll.Name())
+}
+
+// FindByIDsChunked busca documentos por uma lista (possivelmente grande) de _ids, dividindo
+// o $in em consultas de até chunkSize ids cada (padrão 1000 se chunkSize <= 0) e juntando
+// os resultados. Evita montar um $in gigante, que consome memória e pode estourar o limite
+// de 16MB do BSON.
+//
+// Todos os ids são validados antes da primeira consulta; ids inválidos geram erro listando quais.
+// A ordem do resultado não é especificada (não segue a ordem de ids).
+//
+// Exemplo de uso:
+//
+//	users, err := usersRepo.FindByIDsChunked(ctx, ids, 500, monger.Select("name"))
+func (r *Repository[T]) FindByIDsChunked(ctx context.Context, ids []string, chunkSize int, p *ProjectBuilder) (results []T, err error) {
+	ctx, span := r.startSpan(ctx, "FindByIDsChunked")
+	defer func() { span.end(err, int64(len(results))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if chunkSize <= 0 {
+		chunkSize = 1000
+	}
+	oids, err := r.cfg.parseIDs(ids)
 	if err != nil {
 		return nil, err
 	}
-	return &res, nil
+
+	opts := options.Find()
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+
+	results = []T{}
+	for start := 0; start < len(oids); start += chunkSize {
+		end := min(start+chunkSize, len(oids))
+		filter, err := r.scopeFilter(ctx, M{"_id": M{"$in": oids[start:end]}})
+		if err != nil {
+			return nil, err
+		}
+		cursor, err := r.find(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := decodeCursor[T](ctx, cursor, r.coll.Name())
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, chunk...)
+	}
+	return results, nil
 }
 
 // FindAll busca múltiplos documentos com filtro e projeção.
 // O filtro usa busca "fuzzy" (regex case-insensitive) para campos string,
 // permitindo encontrar documentos mesmo com erros de digitação ou nomes parciais.
+// Sem resultados, retorna um slice vazio (nunca nil), que serializa como [] em JSON.
 //
 // Parâmetros:
 //   - ctx: contexto da operação
@@ -327,30 +1193,44 @@ func (r *Repository[T]) Find(ctx context.Context, f *FilterBuilder, p *ProjectBu
 //
 //	// Buscar todos sem limite (cuidado com performance!)
 //	allClients, err := users.FindAll(ctx, nil, nil, 0)
-func (r *Repository[T]) FindAll(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, limit int64) ([]T, error) {
+func (r *Repository[T]) FindAll(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, limit int64) (out []T, err error) {
+	ctx, span := r.startSpan(ctx, "FindAll")
+	defer func() { span.end(err, int64(len(out))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
 	filter := M{}
 	if f != nil {
 		filter = convertToFuzzyFilter(f.Build())
 	}
+	filter, err = r.scopeFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
 
 	opts := options.Find()
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
 	if p != nil {
 		opts.SetProjection(p.Build())
 	}
 	if limit > 0 {
 		opts.SetLimit(limit)
 	}
+	if sort := r.sortOrDefault(nil); sort != nil {
+		opts.SetSort(sort)
+		r.checkSortIndex(ctx, sort)
+	}
 
-	cursor, err := r.coll.Find(ctx, filter, opts)
+	r.logOp("FindAll", func() M {
+		return M{"filter": filter, "projection": opts.Projection, "sort": opts.Sort, "limit": limit}
+	})
+	cursor, err := r.find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
-	var results []T
-	if err := cursor.All(ctx, &results); err != nil {
-		return nil, err
-	}
-	return results, nil
+	return decodeCursor[T](ctx, cursor, r.coll.Name())
 }
 
 // convertToFuzzyFilter converte valores string em regex case-insensitive
@@ -406,26 +1286,110 @@ func escapeRegex(s string) string {
 }
 
 // Count conta documentos baseados em um filtro
-func (r *Repository[T]) Count(ctx context.Context, f *FilterBuilder) (int64, error) {
-	filter := M{}
-	if f != nil {
-		filter = f.Build()
+func (r *Repository[T]) Count(ctx context.Context, f *FilterBuilder) (n int64, err error) {
+	ctx, span := r.startSpan(ctx, "Count")
+	defer func() { span.end(err, n) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	filter, err := r.readFilter(ctx, f)
+	if err != nil {
+		return 0, err
 	}
-	return r.coll.CountDocuments(ctx, filter)
+	r.logOp("Count", func() M { return M{"filter": filter} })
+	return r.countDocuments(ctx, filter, r.countOptions(ctx))
+}
+
+// EstimatedCount retorna uma estimativa do total de documentos da coleção, lida dos
+// metadados (EstimatedDocumentCount), sem varrer a coleção. Por isso não aceita filtro: conta
+// todos os documentos, inclusive os excluídos com WithSoftDelete e os fora de WithRowSecurity.
+// Após um desligamento abrupto do servidor, o valor pode ficar impreciso até a próxima
+// validação da coleção. Para contar com filtro, use Count.
+//
+// Exemplo de uso:
+//
+//	total, err := users.EstimatedCount(ctx) // widget "total de registros"
+func (r *Repository[T]) EstimatedCount(ctx context.Context) (n int64, err error) {
+	ctx, span := r.startSpan(ctx, "EstimatedCount")
+	defer func() { span.end(err, n) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	return r.coll.EstimatedDocumentCount(ctx)
 }
 
 // Exists verifica se existe ao menos um documento que satisfaça o filtro
-func (r *Repository[T]) Exists(ctx context.Context, f *FilterBuilder) (bool, error) {
-	filter := M{}
-	if f != nil {
-		filter = f.Build()
+func (r *Repository[T]) Exists(ctx context.Context, f *FilterBuilder) (ok bool, err error) {
+	ctx, span := r.startSpan(ctx, "Exists")
+	defer func() {
+		var docs int64
+		if ok {
+			docs = 1
+		}
+		span.end(err, docs)
+	}()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	filter, err := r.readFilter(ctx, f)
+	if err != nil {
+		return false, err
 	}
-	count, err := r.coll.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	r.logOp("Exists", func() M { return M{"filter": filter} })
+	count, err := r.countDocuments(ctx, filter, r.countOptions(ctx).SetLimit(1))
 	return count > 0, err
 }
 
+// Distinct retorna os valores distintos de field entre os documentos que satisfazem o filtro
+// (nil considera todos), decodificados em V. Cada valor é decodificado com as mesmas regras do
+// driver para campos de structs (ex.: int32 e int64 cabem em int); se algum não for compatível
+// com V (ex.: dados com tipos misturados), retorna um erro com o valor e a posição.
+// É uma função (e não um método) porque métodos não podem ter parâmetros de tipo em Go.
+//
+// Exemplo de uso:
+//
+//	countries, err := monger.Distinct[string](ctx, users, "country", monger.Filter().Eq("active", true))
+func Distinct[V any, T any](ctx context.Context, r *Repository[T], field string, f *FilterBuilder) (out []V, err error) {
+	ctx, span := r.startSpan(ctx, "Distinct")
+	defer func() { span.end(err, int64(len(out))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if field == "" {
+		return nil, fmt.Errorf("field não pode ser vazio")
+	}
+	filter, err := r.readFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := r.coll.Distinct(ctx, field, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	out = make([]V, len(vals))
+	for i, v := range vals {
+		if typed, ok := v.(V); ok {
+			out[i] = typed
+			continue
+		}
+		if v == nil {
+			continue // null vira o valor zero de V, como em campos de structs
+		}
+		t, data, err := bson.MarshalValue(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := (bson.RawValue{Type: t, Value: data}).Unmarshal(&out[i]); err != nil {
+			return nil, fmt.Errorf("valor distinto %v (posição %d, %s) de %s não pode ser decodificado em %T: %w", v, i, t, field, out[i], err)
+		}
+	}
+	return out, nil
+}
+
 // FindPaged realiza busca com paginação, ordenação e projeção.
 // Se o filtro for nil, retorna todos os documentos respeitando a paginação.
+// Sem resultados, Data é um slice vazio (nunca nil).
+//
+// Por padrão Total é uma contagem exata, feita em paralelo com a busca. Com WithCheapTotals,
+// Total pode ser uma estimativa ou -1 (desconhecido) quando contar exigiria varrer a coleção;
+// com WithoutTotals, a contagem não é feita e Total é -1.
 //
 // Parâmetros:
 //   - ctx: contexto da operação
@@ -442,34 +1406,56 @@ func (r *Repository[T]) Exists(ctx context.Context, f *FilterBuilder) (bool, err
 //
 //	// Listar usuários ativos paginados
 //	res, err := users.FindPaged(ctx, monger.Filter().Eq("active", true), nil, 0, 10, nil)
-func (r *Repository[T]) FindPaged(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, skip, limit int64, sort D) (*PagedResult[T], error) {
-	filter := M{}
-	if f != nil {
-		filter = f.Build()
-	}
-
-	total, err := r.coll.CountDocuments(ctx, filter)
+func (r *Repository[T]) FindPaged(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, skip, limit int64, sort D) (res *PagedResult[T], err error) {
+	ctx, span := r.startSpan(ctx, "FindPaged")
+	defer func() { span.end(err, pageDocs(res)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	filter, err := r.readFilter(ctx, f)
 	if err != nil {
 		return nil, err
 	}
 
 	opts := options.Find()
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
 	if p != nil {
 		opts.SetProjection(p.Build())
 	}
 	opts.SetLimit(limit).SetSkip(skip)
-	if sort != nil {
+	if sort := r.sortOrDefault(sort); sort != nil {
 		opts.SetSort(sort)
+		r.checkSortIndex(ctx, sort)
 	}
 
-	cursor, err := r.coll.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
+	r.logOp("FindPaged", func() M {
+		return M{"filter": filter, "projection": opts.Projection, "sort": opts.Sort, "skip": skip, "limit": limit}
+	})
 
+	// A contagem e a busca rodam em paralelo: a latência é a da mais lenta, e uma falha
+	// cancela a outra. Sessões não podem ser usadas em paralelo: dentro de uma, rodam em sequência.
+	g, gctx := errgroup.WithContext(ctx)
+	if mongo.SessionFromContext(ctx) != nil {
+		g.SetLimit(1)
+	}
+	var total int64
+	g.Go(func() error {
+		var err error
+		total, err = r.pagedTotal(gctx, filter)
+		return err
+	})
 	var data []T
-	if err := cursor.All(ctx, &data); err != nil {
+	g.Go(func() error {
+		cursor, err := r.find(gctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(gctx)
+		data, err = decodeCursor[T](gctx, cursor, r.coll.Name())
+		return err
+	})
+	if err := g.Wait(); err != nil {
 		return nil, err
 	}
 
@@ -479,125 +1465,1060 @@ func (r *Repository[T]) FindPaged(ctx context.Context, f *FilterBuilder, p *Proj
 	}, nil
 }
 
-func parseBsonTag(tag string) (name string, inline bool) {
-	if tag == "" {
-		return "", false
+// FindPage busca a página page (1-based) com size documentos, e preenche os metadados de
+// paginação do resultado (Page, PageSize, TotalPages, HasNext, HasPrev).
+//
+// Páginas fora do intervalo são ajustadas: page < 1 vira 1 e page > TotalPages vira a última
+// página (Page informa a página efetivamente retornada). Com WithCheapTotals e Total
+// desconhecido (-1), TotalPages é -1, a página não é ajustada para baixo e HasNext é
+// calculado buscando um documento a mais.
+//
+// Exemplo de uso:
+//
+//	res, err := users.FindPage(ctx, nil, nil, 3, 20, monger.D{{Key: "name", Value: 1}})
+//	fmt.Printf("página %d de %d\n", res.Page, res.TotalPages)
+func (r *Repository[T]) FindPage(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, page, size int64, sort D) (res *PagedResult[T], err error) {
+	ctx, span := r.startSpan(ctx, "FindPage")
+	defer func() { span.end(err, pageDocs(res)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if size <= 0 {
+		return nil, fmt.Errorf("size deve ser maior que zero")
 	}
-	parts := strings.Split(tag, ",")
-	name = parts[0]
-	for _, opt := range parts[1:] {
-		if opt == "inline" {
-			inline = true
-			break
-		}
+	filter, err := r.readFilter(ctx, f)
+	if err != nil {
+		return nil, err
 	}
-	return name, inline
-}
-
-func buildPartialUpdate(doc any) (M, error) {
-	v := reflect.ValueOf(doc)
-	if !v.IsValid() {
-		return M{}, nil
+	total, err := r.pagedTotal(ctx, filter)
+	if err != nil {
+		return nil, err
 	}
-	if v.Kind() == reflect.Pointer {
-		if v.IsNil() {
-			return M{}, nil
+
+	totalPages := int64(-1)
+	if total >= 0 {
+		totalPages = (total + size - 1) / size
+		if page > totalPages {
+			page = totalPages
 		}
-		v = v.Elem()
 	}
-	if v.Kind() != reflect.Struct {
+	if page < 1 {
+		page = 1
+	}
+
+	opts := options.Find().SetSkip((page - 1) * size).SetLimit(size)
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
+	if total < 0 {
+		opts.SetLimit(size + 1)
+	}
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+	if sort := r.sortOrDefault(sort); sort != nil {
+		opts.SetSort(sort)
+		r.checkSortIndex(ctx, sort)
+	}
+
+	r.logOp("FindPage", func() M {
+		return M{"filter": filter, "projection": opts.Projection, "sort": opts.Sort, "skip": *opts.Skip, "limit": *opts.Limit}
+	})
+	cursor, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	data, err := decodeCursor[T](ctx, cursor, r.coll.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext := page < totalPages
+	if total < 0 {
+		hasNext = int64(len(data)) > size
+		if hasNext {
+			data = data[:size]
+		}
+	}
+	return &PagedResult[T]{
+		Data:       data,
+		Total:      total,
+		Page:       page,
+		PageSize:   size,
+		TotalPages: totalPages,
+		HasNext:    hasNext,
+		HasPrev:    page > 1,
+	}, nil
+}
+
+// FindPageHasMore busca uma página para interfaces do tipo "carregar mais": em vez de contar o
+// total, busca limit+1 documentos e indica em hasMore se existe uma próxima página (data
+// contém no máximo limit documentos). Por dispensar a contagem, é bem mais barato que o
+// FindPaged em filtros complexos ou coleções grandes.
+//
+// Com limit <= 0 não há limite: todos os documentos são retornados e hasMore é false.
+//
+// Exemplo de uso:
+//
+//	items, hasMore, err := posts.FindPageHasMore(ctx, f, nil, page*20, 20, monger.D{{Key: "createdAt", Value: -1}})
+func (r *Repository[T]) FindPageHasMore(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, skip, limit int64, sort D) (data []T, hasMore bool, err error) {
+	ctx, span := r.startSpan(ctx, "FindPageHasMore")
+	defer func() { span.end(err, int64(len(data))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	filter, err := r.readFilter(ctx, f)
+	if err != nil {
+		return nil, false, err
+	}
+
+	opts := options.Find().SetSkip(skip)
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+	if limit > 0 {
+		opts.SetLimit(limit + 1)
+	}
+	if sort := r.sortOrDefault(sort); sort != nil {
+		opts.SetSort(sort)
+		r.checkSortIndex(ctx, sort)
+	}
+
+	cursor, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	data, err = decodeCursor[T](ctx, cursor, r.coll.Name())
+	if err != nil {
+		return nil, false, err
+	}
+	if limit > 0 && int64(len(data)) > limit {
+		return data[:limit], true, nil
+	}
+	return data, false, nil
+}
+
+// sortOrDefault retorna a ordenação informada ou, se ela for vazia, a de WithDefaultSort.
+func (r *Repository[T]) sortOrDefault(sort D) D {
+	if len(sort) > 0 {
+		return sort
+	}
+	return r.cfg.defaultSort
+}
+
+// pagedTotal calcula o Total do FindPaged. Com WithCheapTotals:
+//   - filtro vazio: usa EstimatedDocumentCount (metadados da coleção, sem varredura);
+//   - filtro coberto por índice (plano sem COLLSCAN no explain): contagem exata;
+//   - caso contrário (ou se o explain falhar): -1.
+func (r *Repository[T]) pagedTotal(ctx context.Context, filter M) (int64, error) {
+	if r.cfg.noTotals {
+		return -1, nil
+	}
+	if len(filter) == 0 && (r.cfg.cheapTotals || r.cfg.estimatedTotals) {
+		return r.coll.EstimatedDocumentCount(ctx)
+	}
+	if !r.cfg.cheapTotals {
+		return r.countDocuments(ctx, filter, r.countOptions(ctx))
+	}
+	if !r.countUsesIndex(ctx, filter) {
+		return -1, nil
+	}
+	return r.countDocuments(ctx, filter, r.countOptions(ctx))
+}
+
+// countOptions retorna as opções de CountDocuments das consultas, com a collation do ctx
+// ou do repositório.
+func (r *Repository[T]) countOptions(ctx context.Context) *options.CountOptions {
+	opts := options.Count()
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
+	return opts
+}
+
+// countUsesIndex consulta o plano (explain, verbosidade queryPlanner — sem executar a
+// consulta) e indica se a contagem do filtro dispensa uma varredura completa (COLLSCAN).
+func (r *Repository[T]) countUsesIndex(ctx context.Context, filter M) bool {
+	count := D{{Key: "count", Value: r.coll.Name()}, {Key: "query", Value: filter}}
+	if c := r.cfg.collationFor(ctx); c != nil {
+		count = append(count, bson.E{Key: "collation", Value: bson.Raw(c.ToDocument())})
+	}
+	cmd := D{
+		{Key: "explain", Value: count},
+		{Key: "verbosity", Value: "queryPlanner"},
+	}
+	var plan M
+	if err := r.coll.Database().RunCommand(ctx, cmd).Decode(&plan); err != nil {
+		return false
+	}
+	planner, ok := plan["queryPlanner"]
+	if !ok {
+		return false
+	}
+	return !hasStage(planner, "COLLSCAN")
+}
+
+// hasStage procura recursivamente um estágio com o nome informado em um plano do explain.
+func hasStage(v any, stage string) bool {
+	if elems := docElements(v); elems != nil {
+		for _, e := range elems {
+			if e.Key == "stage" && e.Value == stage {
+				return true
+			}
+			if hasStage(e.Value, stage) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, item := range listElements(v) {
+		if hasStage(item, stage) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseBsonTag(tag string) (name string, inline bool) {
+	if tag == "" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, opt := range parts[1:] {
+		if opt == "inline" {
+			inline = true
+			break
+		}
+	}
+	return name, inline
+}
+
+// buildPartialUpdate monta o $set de um update parcial com os campos não-zerados do struct.
+// Structs aninhados (não inline) viram caminhos pontuados ("address.city"), para não
+// sobrescrever os campos irmãos do subdocumento; a tag `monger:"replace"` no campo grava o
+// subdocumento inteiro. Com WithDottedMaps, o mesmo vale para mapas com chaves string.
+// Os nomes vêm das tags na ordem de WithTagPriority; com WithWriteZeroFields, campos zerados
+// sem omitempty também são gravados.
+func (c *config) buildPartialUpdate(doc any) (M, error) {
+	v := reflect.ValueOf(doc)
+	if !v.IsValid() {
+		return M{}, nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return M{}, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("modelo precisa ser struct ou ponteiro para struct")
 	}
 
-	t := v.Type()
 	update := M{}
+	if err := c.collectPartialUpdate(v, "", update); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// collectPartialUpdate registra em update os campos não-zerados de v, com o prefixo do
+// subdocumento (vazio no nível raiz).
+func (c *config) collectPartialUpdate(v reflect.Value, prefix string, update M) error {
+	t := v.Type()
 	for i := 0; i < v.NumField(); i++ {
 		sf := t.Field(i)
 		if sf.PkgPath != "" { // não-exportado
 			continue
 		}
 
-		tag, inline := parseBsonTag(sf.Tag.Get("bson"))
-		if tag == "-" {
+		tag := c.structTag(sf)
+		name, inline := parseBsonTag(tag)
+		if name == "-" {
 			continue
 		}
 
 		fv := v.Field(i)
 		if inline || (sf.Anonymous && (fv.Kind() == reflect.Struct || (fv.Kind() == reflect.Pointer && fv.Elem().Kind() == reflect.Struct))) {
-			sub, err := buildPartialUpdate(fv.Interface())
-			if err != nil {
-				return nil, err
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
 			}
-			for k, val := range sub {
-				update[k] = val
+			if fv.Kind() == reflect.Struct {
+				if err := c.collectPartialUpdate(fv, prefix, update); err != nil {
+					return err
+				}
 			}
 			continue
 		}
 
-		name := tag
 		if name == "" {
-			name = sf.Name
+			name = strings.ToLower(sf.Name) // mesma regra do driver
 		}
-		if name == "_id" {
+		if prefix == "" && name == "_id" {
 			continue
 		}
+		path := prefix + name
 
 		// Regra: só inclui campos "não-zerados".
 		// Para conseguir setar valores zerados (0, "", false), use ponteiros (*int, *string, *bool) no seu model.
+		// Um mapa nil é ignorado; um mapa vazio (não nil) grava {} e limpa o campo.
 		if fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
 			if fv.IsNil() {
 				continue
 			}
 			if fv.Kind() == reflect.Pointer {
-				update[name] = fv.Elem().Interface()
+				fv = fv.Elem()
+			} else {
+				update[path] = fv.Interface()
 				continue
 			}
-			update[name] = fv.Interface()
+		} else if isZeroField(fv) && (!c.writeZeros || hasTagOption(tag, "omitempty") || (prefix == "" && c.managedField(name))) {
 			continue
 		}
-		if fv.IsZero() {
+
+		replace := hasTagOption(sf.Tag.Get("monger"), "replace")
+		switch {
+		case replace:
+			update[path] = fv.Interface()
+		case isSubdocument(fv.Type()):
+			if err := c.collectPartialUpdate(fv, path+".", update); err != nil {
+				return err
+			}
+		case c.dottedMaps && isDottedMap(fv):
+			if err := collectMapUpdate(fv, path, update); err != nil {
+				return err
+			}
+		default:
+			update[path] = fv.Interface()
+		}
+	}
+	return nil
+}
+
+// structTag retorna a primeira tag não vazia do campo na ordem de WithTagPriority (padrão: bson).
+func (c *config) structTag(sf reflect.StructField) string {
+	if len(c.tagPriority) == 0 {
+		return sf.Tag.Get("bson")
+	}
+	for _, key := range c.tagPriority {
+		if tag := sf.Tag.Get(key); tag != "" {
+			return tag
+		}
+	}
+	return ""
+}
+
+// managedField indica se o campo é mantido pelo repositório (versão, datas automáticas e
+// soft-delete): zerado, nunca é gravado pelo update parcial, nem com WithWriteZeroFields.
+func (c *config) managedField(name string) bool {
+	switch name {
+	case "":
+		return false
+	case c.versionField, c.createdField, c.updatedField, c.softDeleteField:
+		return true
+	}
+	return false
+}
+
+// isDottedMap indica se v é um mapa não vazio com chaves string, a ser gravado chave a chave.
+func isDottedMap(v reflect.Value) bool {
+	return v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String && v.Len() > 0
+}
+
+// collectMapUpdate registra cada chave do mapa como path.chave (recursivamente em mapas
+// aninhados). Chaves vazias, com "." ou iniciadas por "$" não formam caminhos válidos.
+func collectMapUpdate(v reflect.Value, path string, update M) error {
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if key == "" || strings.Contains(key, ".") || strings.HasPrefix(key, "$") {
+			return fmt.Errorf("chave %q de %s não pode virar caminho pontuado", key, path)
+		}
+		val := iter.Value()
+		if val.Kind() == reflect.Interface && !val.IsNil() {
+			val = val.Elem()
+		}
+		if isDottedMap(val) {
+			if err := collectMapUpdate(val, path+"."+key, update); err != nil {
+				return err
+			}
 			continue
 		}
-		update[name] = fv.Interface()
+		update[path+"."+key] = iter.Value().Interface()
 	}
-	return update, nil
+	return nil
 }
 
-// UpdateByID faz update parcial do documento (UpdateOne + $set).
+// isZeroField indica se o valor de um campo (não ponteiro) é zerado e deve ficar fora do update.
+// Para time.Time vale o próprio IsZero: o instante zero em outro fuso (ex.: time.Time{}.In(loc))
+// guarda o *Location e não é zerado para reflect.Value.IsZero, que o gravaria como 0001-01-01.
+func isZeroField(v reflect.Value) bool {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+	return v.IsZero()
+}
+
+// isSubdocument indica se t é um struct gravado como subdocumento (e não um valor BSON
+// como time.Time, os tipos de primitive ou um tipo com codificação própria).
+func isSubdocument(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType || t.PkgPath() == objectIDType.PkgPath() {
+		return false
+	}
+	for _, m := range []reflect.Type{marshalerType, valueMarshalerType} {
+		if t.Implements(m) || reflect.PointerTo(t).Implements(m) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	marshalerType      = reflect.TypeOf((*bson.Marshaler)(nil)).Elem()
+	valueMarshalerType = reflect.TypeOf((*bson.ValueMarshaler)(nil)).Elem()
+)
+
+// UpdateByID faz update parcial do documento (UpdateOne + $set) e retorna as contagens do
+// servidor. Se nenhum documento tiver o ID, retorna ErrNotFound.
 //
 // Por padrão, só inclui campos não-zerados do struct.
 // Para setar valores zerados (0, "", false), use um "patch struct" com campos ponteiro (*int, *string, *bool, etc.).
-func (r *Repository[T]) UpdateByID(ctx context.Context, id string, update any) error {
-	oid, err := primitive.ObjectIDFromHex(id)
+// Structs aninhados viram caminhos pontuados ("address.city"), preservando os demais campos
+// do subdocumento; use a tag `monger:"replace"` para gravar o subdocumento inteiro.
+//
+// Modified == 0 indica que o documento já estava no estado pedido (útil para responder 304).
+// Com WithTimestamps ou WithVersioning, todo update altera o documento (data de atualização e
+// versão), então Modified é sempre 1.
+//
+// Com WithVersioning, a versão é incrementada; se o update trouxer o campo de versão, ele é
+// a versão esperada (ex.: de um If-Match): o update só é aplicado se o documento ainda
+// estiver nela, senão retorna um erro que satisfaz errors.Is(err, ErrVersionConflict).
+//
+// Exemplo de uso:
+//
+//	res, err := users.UpdateByID(ctx, id, &UserPatch{Name: monger.Value("Ana")})
+//	switch {
+//	case errors.Is(err, monger.ErrNotFound):
+//	    // 404
+//	case err == nil && res.Modified == 0:
+//	    // nada mudou
+//	}
+func (r *Repository[T]) UpdateByID(ctx context.Context, id string, update any) (*UpdateResult, error) {
+	if update == nil {
+		return nil, fmt.Errorf("update não pode ser nil")
+	}
+	return r.updateByID(ctx, "UpdateByID", id, update, nil)
+}
+
+// UpdateByIDPatch é o UpdateByID com remoção de campos: aplica o $set do patch (mesmas regras,
+// pode ser nil) e remove do documento os campos de unset (nomes bson, caminhos pontuados
+// aceitos) com $unset. Num patch struct, um ponteiro nil significa "não alterar"; para apagar
+// o campo de vez (e não gravar null), informe-o em unset.
+//
+// O _id, o campo de versão e o de atualização não podem ser removidos (os dois últimos são
+// ignorados); campos imutáveis seguem WithImmutableFields. Um campo não pode estar no patch e
+// em unset ao mesmo tempo.
+//
+// Exemplo de uso:
+//
+//	// troca o apelido e remove o telefone
+//	res, err := users.UpdateByIDPatch(ctx, id, &UserPatch{Nickname: monger.Value("ana")}, "phone")
+//
+//	// só remove
+//	res, err = users.UpdateByIDPatch(ctx, id, nil, "phone", "address.complement")
+func (r *Repository[T]) UpdateByIDPatch(ctx context.Context, id string, update any, unset ...string) (*UpdateResult, error) {
+	return r.updateByID(ctx, "UpdateByIDPatch", id, update, unset)
+}
+
+// updateByID implementa UpdateByID e UpdateByIDPatch; op é o nome da operação no tracing.
+func (r *Repository[T]) updateByID(ctx context.Context, op, id string, update any, unset []string) (out *UpdateResult, err error) {
+	ctx, span := r.startSpan(ctx, op)
+	defer func() { span.end(err, updatedDocs(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if err := r.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+	oid, err := r.cfg.parseID(id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if update == nil {
-		return fmt.Errorf("update não pode ser nil")
+	remove, err := r.unsetFields(unset)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		doc      = M{}
+		expected any
+	)
+	if update != nil {
+		if doc, expected, err = r.partialSet(ctx, update); err != nil {
+			return nil, err
+		}
+	} else if len(remove) == 0 {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	} else {
+		r.touch(doc)
+	}
+	for field := range remove {
+		if _, ok := doc[field]; ok {
+			return nil, fmt.Errorf("campo %q não pode ser gravado e removido no mesmo update", field)
+		}
 	}
 
-	doc, err := buildPartialUpdate(update)
+	filter := M{"_id": oid}
+	if expected != nil {
+		filter[r.cfg.versionField] = expected
+	}
+	u := r.setUpdate(doc)
+	if len(remove) > 0 {
+		u["$unset"] = remove
+	}
+	r.logOp(op, func() M { return M{"filter": filter, "update": u} })
+	res, err := withRetry(ctx, &r.cfg, r.cfg.versionField == "", func() (*mongo.UpdateResult, error) {
+		return r.coll.UpdateOne(ctx, filter, u)
+	})
 	if err != nil {
-		return err
+		return nil, writeError(err)
+	}
+	if res.MatchedCount == 0 {
+		if expected != nil {
+			if err := r.versionConflict(ctx, M{"_id": oid}, expected); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrNotFound
+	}
+	return newUpdateResult(res), nil
+}
+
+// unsetFields monta o documento $unset dos campos informados, sem os campos imutáveis e sem
+// os campos de versão e de atualização. Erro para _id e para nomes inválidos.
+func (r *Repository[T]) unsetFields(fields []string) (M, error) {
+	remove := M{}
+	for _, field := range fields {
+		if field == "" || strings.HasPrefix(field, "$") {
+			return nil, fmt.Errorf("campo %q inválido para $unset", field)
+		}
+		if field == "_id" || strings.HasPrefix(field, "_id.") {
+			return nil, fmt.Errorf("o campo _id não pode ser removido")
+		}
+		remove[field] = ""
+	}
+	if err := r.stripImmutable(remove); err != nil {
+		return nil, err
+	}
+	delete(remove, r.cfg.versionField)
+	delete(remove, r.cfg.updatedField)
+	return remove, nil
+}
+
+// partialSet chama BeforeUpdate e monta o documento $set de um update parcial: campos
+// não-zerados do struct, sem _id e sem campos imutáveis, com o campo de atualização de
+// WithTimestamps. Erro se vazio.
+// Com WithVersioning, o campo de versão é retirado do $set e retornado como versão esperada
+// (nil se o update não o tiver).
+func (r *Repository[T]) partialSet(ctx context.Context, update any) (M, any, error) {
+	if err := beforeUpdate(ctx, update); err != nil {
+		return nil, nil, err
+	}
+	doc, err := r.cfg.buildPartialUpdate(update)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(doc, "_id")
+	if err := r.stripImmutable(doc); err != nil {
+		return nil, nil, err
+	}
+	var expected any
+	if f := r.cfg.versionField; f != "" {
+		expected = doc[f]
+		delete(doc, f)
 	}
 	if len(doc) == 0 {
-		return fmt.Errorf("nenhum campo para atualizar")
+		return nil, nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+	r.touch(doc)
+	return doc, expected, nil
+}
+
+// Upsert aplica o update parcial ($set, mesmas regras do UpdateByID) ao documento que
+// satisfaz o filtro ou, se nenhum existir, insere um novo (com os campos de igualdade do
+// filtro mais os do update). Retorna o _id (hex) quando houve inserção e "" quando foi uma
+// atualização.
+//
+// Campos imutáveis (WithImmutableFields) vão para $setOnInsert: são gravados só na inserção.
+// Por isso um update que só tem campos imutáveis é aceito (não altera documentos existentes);
+// só é erro quando não há nenhum campo para gravar.
+//
+// Exemplo de uso:
+//
+//	id, err := settings.Upsert(ctx, monger.Filter().Eq("userId", uid), &SettingsPatch{Theme: monger.Value("dark")})
+//	if id != "" {
+//	    // configurações criadas agora
+//	}
+func (r *Repository[T]) Upsert(ctx context.Context, f *FilterBuilder, update any) (id string, err error) {
+	ctx, span := r.startSpan(ctx, "Upsert")
+	defer func() { span.end(err, 1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if err := r.ensureSchema(ctx); err != nil {
+		return "", err
+	}
+	if f == nil || len(f.Build()) == 0 {
+		return "", fmt.Errorf("filtro é obrigatório para Upsert")
+	}
+	if update == nil {
+		return "", fmt.Errorf("update não pode ser nil")
+	}
+
+	if err := beforeUpdate(ctx, update); err != nil {
+		return "", err
+	}
+	doc, err := r.cfg.buildPartialUpdate(update)
+	if err != nil {
+		return "", err
+	}
+	delete(doc, "_id")
+	set, onInsert := r.splitImmutable(doc)
+	if len(set) == 0 && len(onInsert) == 0 {
+		return "", fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	opts := options.Update().SetUpsert(true)
+	u := r.touchUpsert(set, onInsert)
+	r.logOp("Upsert", func() M { return M{"filter": f.Build(), "update": u, "upsert": true} })
+	res, err := r.coll.UpdateOne(ctx, f.Build(), u, opts)
+	if err != nil {
+		return "", writeError(err)
+	}
+	if res.UpsertedID == nil {
+		return "", nil
+	}
+	return r.cfg.formatID(res.UpsertedID)
+}
+
+// FindOneAndUpdateOption configura uma chamada de FindOneAndUpdate.
+type FindOneAndUpdateOption func(*options.FindOneAndUpdateOptions)
+
+// UpsertIfMissing faz o FindOneAndUpdate inserir um documento quando nenhum satisfaz o filtro.
+func UpsertIfMissing() FindOneAndUpdateOption {
+	return func(o *options.FindOneAndUpdateOptions) { o.SetUpsert(true) }
+}
+
+// SortBy define qual documento o FindOneAndUpdate escolhe quando vários satisfazem o filtro
+// (ex.: maior prioridade primeiro, em filas).
+func SortBy(sort D) FindOneAndUpdateOption {
+	return func(o *options.FindOneAndUpdateOptions) { o.SetSort(sort) }
+}
+
+// FindOneAndUpdate aplica atomicamente o update parcial ($set, mesmas regras do UpdateByID)
+// ao primeiro documento que satisfaz o filtro e o retorna: com returnNew, o documento já
+// atualizado; senão, como estava antes do update. Retorna ErrNotFound se nenhum documento
+// satisfizer o filtro.
+//
+// Com UpsertIfMissing, um documento é inserido quando nenhum existe (campos imutáveis vão
+// para $setOnInsert); nesse caso, sem returnNew, não há documento anterior e o retorno é
+// (nil, nil). Com SortBy, escolhe qual documento atualizar entre os que satisfazem o filtro.
+//
+// Com WithVersioning, a versão esperada no patch (mesma regra do UpdateByID) entra no filtro:
+// se o documento existe, mas em outra versão, retorna ErrVersionConflict. Com UpsertIfMissing,
+// o campo de versão do patch é ignorado.
+//
+// Exemplo de uso:
+//
+//	// reserva o job pendente de maior prioridade
+//	job, err := jobs.FindOneAndUpdate(ctx,
+//	    monger.Filter().Eq("status", "pending"),
+//	    &JobPatch{Status: monger.Value("running")},
+//	    true,
+//	    monger.SortBy(monger.D{{Key: "priority", Value: -1}, {Key: "createdAt", Value: 1}}),
+//	)
+func (r *Repository[T]) FindOneAndUpdate(ctx context.Context, f *FilterBuilder, update any, returnNew bool, opts ...FindOneAndUpdateOption) (out *T, err error) {
+	ctx, span := r.startSpan(ctx, "FindOneAndUpdate")
+	defer func() { span.end(err, found(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if err := r.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("filtro é obrigatório para FindOneAndUpdate")
+	}
+	if update == nil {
+		return nil, fmt.Errorf("update não pode ser nil")
+	}
+	filter, err := r.scopeFilter(ctx, f.Build())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := beforeUpdate(ctx, update); err != nil {
+		return nil, err
+	}
+	doc, err := r.cfg.buildPartialUpdate(update)
+	if err != nil {
+		return nil, err
 	}
 	delete(doc, "_id")
 
-	_, err = r.coll.UpdateOne(ctx, M{"_id": oid}, M{"$set": doc})
-	return err
+	o := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if returnNew {
+		o.SetReturnDocument(options.After)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	upsert := o.Upsert != nil && *o.Upsert
+
+	var (
+		mods     M
+		expected any
+		guarded  = filter
+	)
+	if upsert {
+		set, onInsert := r.splitImmutable(doc)
+		if len(set) == 0 && len(onInsert) == 0 {
+			return nil, fmt.Errorf("nenhum campo para atualizar")
+		}
+		mods = r.touchUpsert(set, onInsert)
+	} else {
+		if err := r.stripImmutable(doc); err != nil {
+			return nil, err
+		}
+		if f := r.cfg.versionField; f != "" {
+			if expected = doc[f]; expected != nil {
+				guarded = andFilters(filter, M{f: expected})
+			}
+			delete(doc, f)
+		}
+		if len(doc) == 0 {
+			return nil, fmt.Errorf("nenhum campo para atualizar")
+		}
+		r.touch(doc)
+		mods = r.setUpdate(doc)
+	}
+	if sort, ok := o.Sort.(D); ok {
+		r.checkSortIndex(ctx, sort)
+	}
+
+	r.logOp("FindOneAndUpdate", func() M { return M{"filter": guarded, "update": mods, "projection": o.Projection, "sort": o.Sort} })
+	res, err := decodeSingle[T](ctx, r.coll.FindOneAndUpdate(ctx, guarded, mods, o), r.coll.Name())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if upsert && !returnNew {
+			return nil, nil
+		}
+		if expected != nil {
+			if err := r.versionConflict(ctx, filter, expected); err != nil {
+				return nil, err
+			}
+		}
+		return nil, notFound(err)
+	}
+	return res, writeError(err)
 }
 
-// DeleteByID remove um documento por ID
-func (r *Repository[T]) DeleteByID(ctx context.Context, id string) error {
-	oid, err := primitive.ObjectIDFromHex(id)
+// UpdateIfNewer aplica o update parcial ($set, mesmas regras do UpdateByID) somente se o
+// timestamp do evento (ts) for mais recente que o gravado em tsField, e grava ts em tsField
+// junto com o update (last-write-wins). Documentos sem tsField (ou com tsField nulo) aceitam
+// qualquer ts. Documentos ocultos por WithSoftDelete ou WithRowSecurity não são alterados.
+//
+// Retorna true se o update foi aplicado e false se o evento era antigo (ou repetido). Se o
+// documento não existir, retorna ErrNotFound. Ideal para consumidores de eventos que podem
+// chegar fora de ordem.
+//
+// Exemplo de uso:
+//
+//	applied, err := devices.UpdateIfNewer(ctx, ev.DeviceID, "lastEventAt", ev.At, &DevicePatch{Status: &ev.Status})
+func (r *Repository[T]) UpdateIfNewer(ctx context.Context, id string, tsField string, ts time.Time, update any) (applied bool, err error) {
+	ctx, span := r.startSpan(ctx, "UpdateIfNewer")
+	defer func() {
+		var docs int64
+		if applied {
+			docs = 1
+		}
+		span.end(err, docs)
+	}()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	oid, err := r.cfg.parseID(id)
 	if err != nil {
-		return err
+		return false, err
+	}
+	if tsField == "" {
+		return false, fmt.Errorf("tsField não pode ser vazio")
+	}
+	if update == nil {
+		return false, fmt.Errorf("update não pode ser nil")
 	}
-	_, err = r.coll.DeleteOne(ctx, M{"_id": oid})
-	return err
+	if err := r.ensureSchema(ctx); err != nil {
+		return false, err
+	}
+
+	if err := beforeUpdate(ctx, update); err != nil {
+		return false, err
+	}
+	doc, err := r.cfg.buildPartialUpdate(update)
+	if err != nil {
+		return false, err
+	}
+	delete(doc, "_id")
+	if err := r.stripImmutable(doc); err != nil {
+		return false, err
+	}
+	doc[tsField] = ts
+	r.touch(doc)
+
+	scoped, err := r.scopeFilter(ctx, M{"_id": oid})
+	if err != nil {
+		return false, err
+	}
+	// {tsField: null} casa tanto o campo ausente quanto o valor nulo
+	filter := andFilters(scoped, M{"$or": []M{
+		{tsField: M{"$lt": ts}},
+		{tsField: nil},
+	}})
+	mods := r.setUpdate(doc)
+	r.logOp("UpdateIfNewer", func() M { return M{"filter": filter, "update": mods} })
+	res, err := r.coll.UpdateOne(ctx, filter, mods)
+	if err != nil {
+		return false, writeError(err)
+	}
+	if res.MatchedCount > 0 {
+		return true, nil
+	}
+
+	// Não aplicado: evento antigo ou documento inexistente (ou oculto pelas restrições)
+	n, err := r.coll.CountDocuments(ctx, scoped, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, ErrNotFound
+	}
+	return false, nil
+}
+
+// UpdateByIDs aplica o mesmo update parcial ($set, mesmas regras do UpdateByID) aos documentos
+// com os IDs informados. O resultado distingue os documentos encontrados (Matched) dos
+// efetivamente alterados (Modified): a diferença é quantos já estavam no estado desejado.
+//
+// Exemplo de uso:
+//
+//	res, err := notifications.UpdateByIDs(ctx, ids, &NotificationPatch{Read: monger.Value(true)})
+//	fmt.Printf("%d de %d já estavam lidas\n", res.Matched-res.Modified, len(ids))
+func (r *Repository[T]) UpdateByIDs(ctx context.Context, ids []string, update any) (out *UpdateResult, err error) {
+	ctx, span := r.startSpan(ctx, "UpdateByIDs")
+	defer func() { span.end(err, updatedDocs(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	oids, err := r.cfg.parseIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(oids) == 0 {
+		return &UpdateResult{}, nil
+	}
+	return r.updateMany(ctx, M{"_id": M{"$in": oids}}, update)
+}
+
+// UpdateMany aplica o mesmo update parcial ($set, mesmas regras do UpdateByID) a todos os
+// documentos que satisfazem o filtro, e retorna as contagens do servidor.
+// O filtro é obrigatório e não pode ser vazio, para evitar atualizar a coleção inteira por engano.
+//
+// Exemplo de uso:
+//
+//	res, err := users.UpdateMany(ctx, monger.Filter().Lt("lastLogin", cutoff), &UserPatch{Status: monger.Value("inactive")})
+//	fmt.Printf("%d encontrados, %d alterados\n", res.Matched, res.Modified)
+func (r *Repository[T]) UpdateMany(ctx context.Context, f *FilterBuilder, update any) (out *UpdateResult, err error) {
+	ctx, span := r.startSpan(ctx, "UpdateMany")
+	defer func() { span.end(err, updatedDocs(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if f == nil || len(f.Build()) == 0 {
+		return nil, fmt.Errorf("filtro é obrigatório para UpdateMany (para atualizar todos os documentos, use UpdateAll)")
+	}
+	return r.updateMany(ctx, f.Build(), update)
+}
+
+// UpdateAll aplica o update parcial a todos os documentos da coleção. É a forma explícita (e
+// visível na chamada) de fazer o que UpdateMany recusa com filtro vazio.
+//
+// Exemplo de uso:
+//
+//	res, err := users.UpdateAll(ctx, &UserPatch{Plan: monger.Value("free")})
+func (r *Repository[T]) UpdateAll(ctx context.Context, update any) (out *UpdateResult, err error) {
+	ctx, span := r.startSpan(ctx, "UpdateAll")
+	defer func() { span.end(err, updatedDocs(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	return r.updateMany(ctx, M{}, update)
+}
+
+// updateMany monta o $set parcial e aplica a todos os documentos do filtro.
+func (r *Repository[T]) updateMany(ctx context.Context, filter M, update any) (*UpdateResult, error) {
+	if err := r.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+	if update == nil {
+		return nil, fmt.Errorf("update não pode ser nil")
+	}
+
+	doc, expected, err := r.partialSet(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+	if expected != nil {
+		filter = andFilters(filter, M{r.cfg.versionField: expected})
+	}
+	u := r.setUpdate(doc)
+	r.logOp("UpdateMany", func() M { return M{"filter": filter, "update": u} })
+	res, err := withRetry(ctx, &r.cfg, r.cfg.versionField == "", func() (*mongo.UpdateResult, error) {
+		return r.coll.UpdateMany(ctx, filter, u)
+	})
+	if err != nil {
+		return nil, writeError(err)
+	}
+	return newUpdateResult(res), nil
+}
+
+// DeleteByID remove um documento por ID e retorna quantos foram removidos (0 se nenhum
+// documento tinha o ID, 1 caso contrário).
+// Com WithSoftDelete, o documento é apenas marcado como excluído (e as cascatas são executadas);
+// um documento já excluído conta como 0.
+//
+// Exemplo de uso:
+//
+//	n, err := users.DeleteByID(ctx, id)
+//	if err == nil && n == 0 {
+//	    // 404
+//	}
+func (r *Repository[T]) DeleteByID(ctx context.Context, id string) (n int64, err error) {
+	ctx, span := r.startSpan(ctx, "DeleteByID")
+	defer func() { span.end(err, n) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	oid, err := r.cfg.parseID(id)
+	if err != nil {
+		return 0, err
+	}
+	r.logOp("DeleteByID", func() M { return M{"filter": M{"_id": oid}, "softDelete": r.cfg.softDeleteField != ""} })
+	if r.cfg.softDeleteField != "" {
+		return r.softDelete(ctx, M{"_id": oid})
+	}
+	res, err := withRetry(ctx, &r.cfg, true, func() (*mongo.DeleteResult, error) {
+		return r.coll.DeleteOne(ctx, M{"_id": oid})
+	})
+	if err != nil {
+		return 0, writeError(err)
+	}
+	return res.DeletedCount, nil
+}
+
+// DeleteMany remove os documentos que satisfazem o filtro e retorna quantos foram removidos.
+// Com WithSoftDelete, os documentos são apenas marcados como excluídos (e as cascatas são
+// executadas); a contagem é de documentos marcados.
+// O filtro é obrigatório e não pode ser vazio, para evitar apagar a coleção inteira por engano.
+//
+// Exemplo de uso:
+//
+//	n, err := sessions.DeleteMany(ctx, monger.Filter().Lt("expiresAt", time.Now()))
+func (r *Repository[T]) DeleteMany(ctx context.Context, f *FilterBuilder) (n int64, err error) {
+	ctx, span := r.startSpan(ctx, "DeleteMany")
+	defer func() { span.end(err, n) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if f == nil || len(f.Build()) == 0 {
+		return 0, fmt.Errorf("filtro é obrigatório para DeleteMany (para remover todos os documentos, use DeleteAll)")
+	}
+	return r.deleteMany(ctx, f.Build())
+}
+
+// DeleteAll remove todos os documentos da coleção (com WithSoftDelete, marca todos como
+// excluídos). É a forma explícita (e visível na chamada) de fazer o que DeleteMany recusa
+// com filtro vazio. Índices e validador da coleção são mantidos.
+func (r *Repository[T]) DeleteAll(ctx context.Context) (n int64, err error) {
+	ctx, span := r.startSpan(ctx, "DeleteAll")
+	defer func() { span.end(err, n) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	return r.deleteMany(ctx, M{})
+}
+
+// deleteMany remove (ou marca como excluídos) os documentos do filtro.
+func (r *Repository[T]) deleteMany(ctx context.Context, filter M) (int64, error) {
+	r.logOp("DeleteMany", func() M { return M{"filter": filter, "softDelete": r.cfg.softDeleteField != ""} })
+	if r.cfg.softDeleteField != "" {
+		return r.softDelete(ctx, filter)
+	}
+	res, err := withRetry(ctx, &r.cfg, true, func() (*mongo.DeleteResult, error) {
+		return r.coll.DeleteMany(ctx, filter)
+	})
+	if err != nil {
+		return 0, writeError(err)
+	}
+	return res.DeletedCount, nil
+}
+
+// FindOneAndDelete remove atomicamente o primeiro documento que satisfaz o filtro (na ordem
+// de sort, se informado; nil usa WithDefaultSort) e o retorna — semântica de "pop" para filas
+// de prioridade. Retorna ErrNotFound se nenhum documento satisfizer o filtro. A projeção é
+// opcional e afeta só o documento retornado.
+//
+// Com WithSoftDelete, o documento é marcado como excluído (e as cascatas são executadas)
+// em vez de removido.
+//
+// Exemplo de uso:
+//
+//	task, err := tasks.FindOneAndDelete(ctx, monger.Filter().Eq("status", "pending"), nil, monger.D{{Key: "priority", Value: -1}})
+//	if errors.Is(err, monger.ErrNotFound) {
+//	    // fila vazia
+//	}
+func (r *Repository[T]) FindOneAndDelete(ctx context.Context, f *FilterBuilder, p *ProjectBuilder, sort D) (out *T, err error) {
+	ctx, span := r.startSpan(ctx, "FindOneAndDelete")
+	defer func() { span.end(err, found(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if f == nil {
+		return nil, fmt.Errorf("filtro é obrigatório para FindOneAndDelete")
+	}
+	filter, err := r.scopeFilter(ctx, f.Build())
+	if err != nil {
+		return nil, err
+	}
+	sort = r.sortOrDefault(sort)
+	r.checkSortIndex(ctx, sort)
+
+	r.logOp("FindOneAndDelete", func() M {
+		return M{"filter": filter, "projection": projectionOf(p), "sort": sort, "softDelete": r.cfg.softDeleteField != ""}
+	})
+	var res *mongo.SingleResult
+	if r.cfg.softDeleteField != "" {
+		res, err = r.softDeleteOne(ctx, filter, p, sort)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		opts := options.FindOneAndDelete()
+		if p != nil {
+			opts.SetProjection(p.Build())
+		}
+		if sort != nil {
+			opts.SetSort(sort)
+		}
+		res = r.coll.FindOneAndDelete(ctx, filter, opts)
+	}
+
+	doc, err := decodeSingle[T](ctx, res, r.coll.Name())
+	return doc, writeError(notFound(err))
 }
 
 // --- JOIN (união de coleções) ---
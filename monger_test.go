@@ -0,0 +1,433 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: monger_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes do FilterBuilder (documentos gerados por Build()) e do update parcial,
+	sem banco de dados.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// assertFilter compara o filtro gerado com o esperado.
+func assertFilter(t *testing.T, got, want M) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filtro = %#v\nesperado %#v", got, want)
+	}
+}
+
+func TestFilterSameFieldPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		f    *FilterBuilder
+		want M
+	}{
+		{"gt+lt", Filter().Gt("age", 18).Lt("age", 65), M{"age": M{"$gt": 18, "$lt": 65}}},
+		{"gte+lte", Filter().Gte("age", 18).Lte("age", 65), M{"age": M{"$gte": 18, "$lte": 65}}},
+		{"ne+exists", Filter().Ne("email", "").Exists("email", true), M{"email": M{"$ne": "", "$exists": true}}},
+		{"igualdade depois de operador", Filter().Gt("age", 18).Eq("age", 30), M{"age": 30}},
+		{"operador depois de igualdade", Filter().Eq("age", 30).Gt("age", 18), M{"age": M{"$eq": 30, "$gt": 18}}},
+		{
+			"operador depois de regex simples",
+			Filter().RawField("name", primitive.Regex{Pattern: "^jo"}).Ne("name", "joe"),
+			M{"name": M{"$regex": primitive.Regex{Pattern: "^jo"}, "$ne": "joe"}},
+		},
+		{
+			"exists+not",
+			Filter().Exists("name", true).Not("name", Filter().StartsWith("name", "test")),
+			M{"name": M{"$exists": true, "$not": M{"$regex": primitive.Regex{Pattern: "^test"}}}},
+		},
+		{
+			"exists+near",
+			Filter().Exists("loc", true).Near("loc", -46.63, -23.55, 0),
+			M{"loc": M{"$exists": true, "$near": M{"$geometry": GeoPoint(-46.63, -23.55)}}},
+		},
+		{
+			"exists+geoWithin",
+			Filter().Exists("loc", true).GeoWithin("loc", M{"type": "Polygon"}),
+			M{"loc": M{"$exists": true, "$geoWithin": M{"$geometry": M{"type": "Polygon"}}}},
+		},
+		{
+			"or+or acumula",
+			Filter().Or(Filter().Eq("a", 1)).Or(Filter().Eq("b", 2)),
+			M{"$or": []M{{"a": 1}, {"b": 2}}},
+		},
+		{
+			"and+and acumula",
+			Filter().And(Filter().Eq("a", 1)).And(Filter().Eq("b", 2)),
+			M{"$and": []M{{"a": 1}, {"b": 2}}},
+		},
+		{
+			"nor depois de Raw",
+			Filter().Raw(M{"$nor": []any{M{"a": 1}}}).Nor(Filter().Eq("b", 2)),
+			M{"$nor": []any{M{"a": 1}, M{"b": 2}}},
+		},
+		{
+			"exists+nonEmpty",
+			Filter().Exists("email", true).Ne("email", "x").NonEmpty("email"),
+			M{"email": M{"$exists": true, "$ne": "x", "$nin": []any{nil, ""}}},
+		},
+		{
+			"regex+ne",
+			Filter().Regex("name", "^jo", "i").Ne("name", "joe"),
+			M{"name": M{"$regex": primitive.Regex{Pattern: "^jo", Options: "i"}, "$ne": "joe"}},
+		},
+		{
+			"regex substitui o anterior, com as opções",
+			Filter().Regex("name", "^jo", "i").Regex("name", "^ma"),
+			M{"name": M{"$regex": primitive.Regex{Pattern: "^ma"}}},
+		},
+		{
+			"exists+elemMatch",
+			Filter().Exists("items", true).ElemMatch("items", Filter().Gt("qty", 2)),
+			M{"items": M{"$exists": true, "$elemMatch": M{"qty": M{"$gt": 2}}}},
+		},
+		{
+			"allElemMatch+exists",
+			Filter().Exists("grades", true).AllElemMatch("grades", Filter().Eq("subject", "math")),
+			M{"grades": M{"$exists": true, "$all": []M{{"$elemMatch": M{"subject": "math"}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFilter(t, tt.f.Build(), tt.want)
+		})
+	}
+}
+
+func TestFilterRegexIsBSONRegex(t *testing.T) {
+	raw, err := bson.Marshal(Filter().Contains("name", "jo", "i").Ne("name", "joe").Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := bson.Raw(raw).Lookup("name", "$regex")
+	if v.Type != bson.TypeRegex {
+		t.Fatalf("$regex gravado como %v, esperado regex BSON", v.Type)
+	}
+	if pattern, opts := v.Regex(); pattern != "jo" || opts != "i" {
+		t.Errorf("$regex = /%s/%s, esperado /jo/i", pattern, opts)
+	}
+}
+
+func TestFilterRegexDescribe(t *testing.T) {
+	got := Filter().Regex("email", "@gmail", "i").Describe()
+	if want := "email ~ /@gmail/i"; got != want {
+		t.Errorf("Describe() = %q, esperado %q", got, want)
+	}
+}
+
+func TestPartialUpdateTime(t *testing.T) {
+	type sessionPatch struct {
+		LastSeen  time.Time  `bson:"lastSeen"`
+		ExpiresAt *time.Time `bson:"expiresAt"`
+	}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	brt := time.FixedZone("BRT", -3*3600)
+
+	tests := []struct {
+		name  string
+		patch sessionPatch
+		want  M
+	}{
+		{"time.Time zerado é ignorado", sessionPatch{}, M{}},
+		// O instante zero em outro fuso guarda o *Location: reflect.Value.IsZero o veria
+		// como preenchido e gravaria 0001-01-01 no documento.
+		{"time.Time zerado em outro fuso é ignorado", sessionPatch{LastSeen: time.Time{}.In(brt)}, M{}},
+		{"time.Time preenchido é gravado", sessionPatch{LastSeen: now}, M{"lastSeen": now}},
+		{"*time.Time com Value(time.Time{}) é gravado", sessionPatch{ExpiresAt: Value(time.Time{})}, M{"expiresAt": time.Time{}}},
+		{"*time.Time preenchido é gravado", sessionPatch{ExpiresAt: Value(now)}, M{"expiresAt": now}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&config{}).buildPartialUpdate(&tt.patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("update = %#v\nesperado %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNonEmpty(t *testing.T) {
+	assertFilter(t, Filter().NonEmpty("email").Build(), M{
+		"email": M{"$exists": true, "$nin": []any{nil, ""}},
+	})
+	assertFilter(t, Filter().NonEmptyArray("tags").Build(), M{
+		"tags": M{"$exists": true, "$type": "array", "$not": M{"$size": 0}},
+	})
+	assertFilter(t, Filter().NonEmpty("email").NonEmptyArray("tags").Build(), M{
+		"email": M{"$exists": true, "$nin": []any{nil, ""}},
+		"tags":  M{"$exists": true, "$type": "array", "$not": M{"$size": 0}},
+	})
+}
+
+func TestAllElemMatch(t *testing.T) {
+	f := Filter().AllElemMatch("grades",
+		Filter().Eq("subject", "math").Gt("score", 90),
+		Filter().Eq("subject", "science").Gt("score", 80),
+		nil,
+	)
+	assertFilter(t, f.Build(), M{"grades": M{"$all": []M{
+		{"$elemMatch": M{"subject": "math", "score": M{"$gt": 90}}},
+		{"$elemMatch": M{"subject": "science", "score": M{"$gt": 80}}},
+	}}})
+
+	assertFilter(t, Filter().AllElemMatch("grades").Build(), M{})
+}
+
+func TestAllTypedSlice(t *testing.T) {
+	assertFilter(t, Filter().All("tags", []string{"go", "db"}).Build(), M{"tags": M{"$all": []string{"go", "db"}}})
+	assertFilter(t, Filter().All("tags", []string(nil)).Build(), M{"tags": M{"$all": []any{}}})
+
+	type post struct {
+		ID   string   `bson:"_id"`
+		Tags []string `bson:"tags"`
+	}
+	mockRepo(t, nil, func(t *testing.T, mt *mtest.T, r *Repository[post]) {
+		mt.AddMockResponses(cursorReply(mt, bson.D{{Key: "_id", Value: "p1"}, {Key: "tags", Value: bson.A{"go", "db"}}}))
+		posts, err := r.FindAll(context.Background(), Filter().All("tags", []string{"go", "db"}), nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(posts) != 1 || posts[0].ID != "p1" {
+			t.Errorf("posts = %v", posts)
+		}
+
+		// O slice tipado chega ao servidor como um array BSON simples, sem camada extra
+		all := sentCommand(t, mt).Lookup("filter", "tags", "$all")
+		vals, err := all.Array().Values()
+		if err != nil {
+			t.Fatalf("$all não é um array: %v", all)
+		}
+		if len(vals) != 2 || vals[0].StringValue() != "go" || vals[1].StringValue() != "db" {
+			t.Errorf("$all = %v, esperado [go db]", all)
+		}
+	})
+}
+
+func TestNorAndNot(t *testing.T) {
+	tests := []struct {
+		name string
+		f    *FilterBuilder
+		want M
+	}{
+		{
+			"$nor no nível do documento",
+			Filter().Nor(Filter().Eq("status", "banned"), Filter().Lt("age", 18)),
+			M{"$nor": []M{{"status": "banned"}, {"age": M{"$lt": 18}}}},
+		},
+		{
+			"$not no operador de um campo",
+			Filter().Not("age", Filter().Gte("age", 18).Lt("age", 65)),
+			M{"age": M{"$not": M{"$gte": 18, "$lt": 65}}},
+		},
+		{
+			"$not de regex",
+			Filter().Not("name", Filter().StartsWith("name", "test")),
+			M{"name": M{"$not": M{"$regex": primitive.Regex{Pattern: "^test"}}}},
+		},
+		{"$not de igualdade vira $eq", Filter().Not("status", Filter().Eq("status", "active")), M{"status": M{"$not": M{"$eq": "active"}}}},
+		{"sub sem o campo não adiciona nada", Filter().Not("age", Filter().Eq("name", "x")), M{}},
+		{
+			"$not ao lado de outros campos",
+			Filter().Eq("role", "admin").Not("email", Filter().EndsWith("email", "@test.com")),
+			M{"role": "admin", "email": M{"$not": M{"$regex": primitive.Regex{Pattern: `@test\.com$`}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFilter(t, tt.f.Build(), tt.want)
+		})
+	}
+}
+
+func TestDeleteManyCount(t *testing.T) {
+	type job struct {
+		ID     string `bson:"_id"`
+		Status string `bson:"status"`
+	}
+	ctx := context.Background()
+
+	mockRepo(t, nil, func(t *testing.T, mt *mtest.T, r *Repository[job]) {
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 3}))
+		n, err := r.DeleteMany(ctx, Filter().Eq("status", "done"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 3 {
+			t.Errorf("DeleteMany = %d, esperado 3 (documentos que satisfizeram o filtro)", n)
+		}
+		q := sentCommand(t, mt).Lookup("deletes", "0", "q", "status")
+		if q.StringValue() != "done" {
+			t.Errorf("filtro enviado = %v", q)
+		}
+
+		if _, err := r.DeleteMany(ctx, nil); err == nil {
+			t.Error("DeleteMany(nil) deveria retornar erro")
+		}
+		if _, err := r.DeleteMany(ctx, Filter()); err == nil {
+			t.Error("DeleteMany com filtro vazio deveria retornar erro")
+		}
+
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 7}))
+		if n, err := r.DeleteAll(ctx); err != nil || n != 7 {
+			t.Errorf("DeleteAll = %d, %v; esperado 7", n, err)
+		}
+	})
+
+	mockRepo(t, []Option{WithSoftDelete("deletedAt")}, func(t *testing.T, mt *mtest.T, r *Repository[job]) {
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+		n, err := r.DeleteMany(ctx, Filter().Eq("status", "done"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("DeleteMany com soft-delete = %d, esperado 2", n)
+		}
+	})
+}
+
+func TestDistinctMixedTypes(t *testing.T) {
+	type user struct {
+		ID      string `bson:"_id"`
+		Country string `bson:"country"`
+	}
+	ctx := context.Background()
+	distinctReply := func(vals ...any) bson.D {
+		return okReply(bson.E{Key: "values", Value: bson.A(vals)})
+	}
+
+	mockRepo(t, nil, func(t *testing.T, mt *mtest.T, r *Repository[user]) {
+		mt.AddMockResponses(distinctReply("BR", "US"))
+		countries, err := Distinct[string](ctx, r, "country", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(countries, []string{"BR", "US"}) {
+			t.Errorf("Distinct = %v", countries)
+		}
+
+		mt.AddMockResponses(distinctReply(int32(1), int64(2)))
+		nums, err := Distinct[int](ctx, r, "level", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(nums, []int{1, 2}) {
+			t.Errorf("Distinct[int] = %v", nums)
+		}
+
+		// Tipos misturados: erro com o valor e a posição, sem panic
+		mt.AddMockResponses(distinctReply("BR", int32(42)))
+		_, err = Distinct[string](ctx, r, "country", nil)
+		if err == nil {
+			t.Fatal("Distinct com tipos misturados deveria retornar erro")
+		}
+		if msg := err.Error(); !strings.Contains(msg, "42") || !strings.Contains(msg, "posição 1") {
+			t.Errorf("erro pouco informativo: %v", err)
+		}
+	})
+}
+
+func TestFindOneNotFoundKeepsDriverError(t *testing.T) {
+	type user struct {
+		ID    string `bson:"_id"`
+		Email string `bson:"email"`
+	}
+	mockRepo(t, nil, func(t *testing.T, mt *mtest.T, r *Repository[user]) {
+		mt.AddMockResponses(cursorReply(mt))
+		_, err := r.FindOne(context.Background(), Filter().Eq("email", "x@y.z"), nil, nil)
+		if !errors.Is(err, ErrNotFound) || !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Errorf("FindOne sem documento = %v, esperado ErrNotFound envolvendo mongo.ErrNoDocuments", err)
+		}
+	})
+}
+
+func TestInsertWithIDDuplicate(t *testing.T) {
+	type event struct {
+		ID      string `bson:"_id,omitempty"`
+		Payload string `bson:"payload"`
+	}
+	mockRepo(t, nil, func(t *testing.T, mt *mtest.T, r *Repository[event]) {
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key error"}))
+		err := r.InsertWithID(context.Background(), "hash-1", &event{Payload: "x"})
+		if !errors.Is(err, ErrDuplicateKey) || !mongo.IsDuplicateKeyError(err) {
+			t.Errorf("InsertWithID com colisão = %v, esperado ErrDuplicateKey envolvendo o erro do driver", err)
+		}
+		if id := sentCommand(t, mt).Lookup("documents", "0", "_id"); id.StringValue() != "hash-1" {
+			t.Errorf("_id enviado = %v", id)
+		}
+
+		if err := r.InsertWithID(context.Background(), "hash-2", &event{ID: "other"}); err == nil {
+			t.Error("InsertWithID com _id divergente deveria retornar erro")
+		}
+	})
+}
+
+func TestUpdateIfNewerFilter(t *testing.T) {
+	type device struct {
+		ID          primitive.ObjectID `bson:"_id,omitempty"`
+		Status      string             `bson:"status"`
+		LastEventAt *time.Time         `bson:"lastEventAt"`
+	}
+	type devicePatch struct {
+		Status string `bson:"status"`
+	}
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mockRepo(t, []Option{WithSoftDelete("deletedAt")}, func(t *testing.T, mt *mtest.T, r *Repository[device]) {
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		applied, err := r.UpdateIfNewer(ctx, id.Hex(), "lastEventAt", at, &devicePatch{Status: "on"})
+		if err != nil || !applied {
+			t.Fatalf("UpdateIfNewer = %v, %v; esperado aplicado", applied, err)
+		}
+		q := sentCommand(t, mt).Lookup("updates", "0", "q").String()
+		for _, part := range []string{`{"deletedAt": null}`, `{"lastEventAt": null}`, `"$lt"`} {
+			if !strings.Contains(q, part) {
+				t.Errorf("filtro %s não contém %s", q, part)
+			}
+		}
+
+		// Evento antigo: não aplicado, e o documento existe entre os visíveis
+		mt.AddMockResponses(
+			okReply(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			cursorReply(mt, bson.D{{Key: "n", Value: 1}}),
+		)
+		applied, err = r.UpdateIfNewer(ctx, id.Hex(), "lastEventAt", at, &devicePatch{Status: "off"})
+		if err != nil || applied {
+			t.Errorf("UpdateIfNewer com evento antigo = %v, %v; esperado false, nil", applied, err)
+		}
+		sentCommand(t, mt) // o update que não casou
+		if match := sentCommand(t, mt).Lookup("pipeline", "0", "$match").String(); !strings.Contains(match, `{"deletedAt": null}`) {
+			t.Errorf("verificação de existência ignora o soft-delete: %s", match)
+		}
+	})
+}
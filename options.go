@@ -0,0 +1,334 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: options.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define as opções de configuração do Repository[T],
+	informadas em New(db, collectionName, opts...).
+*/
+package monger
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configura o comportamento de um Repository em New.
+type Option func(*config)
+
+// config reúne as configurações aplicadas pelas Options.
+type config struct {
+	allowDiskUse    bool
+	cheapTotals     bool
+	estimatedTotals bool
+	noTotals        bool
+	defaultSort     D
+	collation       *options.Collation
+	rowSecurity     func(ctx context.Context) (*FilterBuilder, error)
+	timeout         time.Duration
+	tracer          trace.Tracer
+	logger          func(op string, payload M)
+	retryAttempts   int
+	retryBackoff    time.Duration
+	idCodec         IDCodec
+
+	readPref     *readpref.ReadPref
+	readConcern  *readconcern.ReadConcern
+	writeConcern *writeconcern.WriteConcern
+
+	softDeleteField string
+	onSoftDelete    []func(ctx context.Context, deletedID string) error
+
+	versionField string
+
+	createdField string
+	updatedField string
+
+	schemaValidation bool
+
+	immutableFields []string
+	rejectImmutable bool
+
+	dottedMaps  bool
+	tagPriority []string
+	writeZeros  bool
+
+	explainWarnings bool
+	warn            func(msg string)
+}
+
+// WithTimeout define um prazo padrão para cada operação do repositório: o ctx recebido é
+// envolvido com context.WithTimeout(ctx, d), o que protege contra consultas que nunca
+// terminam. Se o ctx já tiver um prazo menor, ele prevalece (vale sempre o mais cedo).
+//
+// As iterações (Iterate, Stream, ForEachResumable, ForEachWithProgress) não recebem o
+// timeout, pois duram o tempo que o processamento precisar; controle-as pelo ctx.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) { c.timeout = d }
+}
+
+// WithReadPreference define de quais membros do replica set o repositório lê (ex.:
+// readpref.SecondaryPreferred() para relatórios, tirando carga do primário). Leituras em
+// secundários podem estar atrasadas em relação às últimas escritas.
+//
+// Exemplo de uso:
+//
+//	reports := monger.New[Order](db, "orders", monger.WithReadPreference(readpref.SecondaryPreferred()))
+func WithReadPreference(rp *readpref.ReadPref) Option {
+	return func(c *config) { c.readPref = rp }
+}
+
+// WithReadConcern define o nível de consistência das leituras (ex.: readconcern.Majority()
+// para ler apenas dados confirmados pela maioria do replica set).
+func WithReadConcern(rc *readconcern.ReadConcern) Option {
+	return func(c *config) { c.readConcern = rc }
+}
+
+// WithWriteConcern define a confirmação exigida das escritas (ex.: writeconcern.Majority()
+// para escritas financeiras, que só retornam depois de replicadas na maioria dos membros).
+//
+// Exemplo de uso:
+//
+//	ledger := monger.New[Entry](db, "ledger", monger.WithWriteConcern(writeconcern.Majority()))
+func WithWriteConcern(wc *writeconcern.WriteConcern) Option {
+	return func(c *config) { c.writeConcern = wc }
+}
+
+// collectionOptions monta as opções da coleção (read preference, read/write concern); sem
+// elas, valem as do database/cliente.
+func (c *config) collectionOptions() *options.CollectionOptions {
+	opts := options.Collection()
+	if c.readPref != nil {
+		opts.SetReadPreference(c.readPref)
+	}
+	if c.readConcern != nil {
+		opts.SetReadConcern(c.readConcern)
+	}
+	if c.writeConcern != nil {
+		opts.SetWriteConcern(c.writeConcern)
+	}
+	return opts
+}
+
+// WithAllowDiskUse permite que as agregações do repositório usem arquivos temporários
+// em disco quando um estágio ($group, $sort, ...) excede o limite de 100MB de memória.
+//
+// Sem essa opção, agregações grandes falham com erro; com ela, completam, porém mais
+// devagar, já que o servidor passa a ler/escrever em disco.
+func WithAllowDiskUse() Option {
+	return func(c *config) { c.allowDiskUse = true }
+}
+
+// WithCheapTotals faz o FindPaged calcular o total exato só quando isso é barato:
+//   - sem filtro, o total é estimado pelos metadados da coleção (EstimatedDocumentCount);
+//   - com filtro, um explain (sem execução) verifica se a contagem usa índice; se usar, o
+//     total é exato, senão Total vem como -1 (desconhecido).
+//
+// Com Total -1, use paginação do tipo "carregar mais" em vez de exibir o total.
+func WithCheapTotals() Option {
+	return func(c *config) { c.cheapTotals = true }
+}
+
+// WithEstimatedTotals faz o FindPaged (e o FindPage) usar a estimativa dos metadados da
+// coleção (EstimatedDocumentCount) como Total quando não há filtro (nem restrições do
+// repositório, como WithSoftDelete e WithRowSecurity); com filtro, a contagem continua exata.
+// Útil em listagens sem filtro de coleções grandes, em que o total exato não é necessário.
+func WithEstimatedTotals() Option {
+	return func(c *config) { c.estimatedTotals = true }
+}
+
+// WithoutTotals faz o FindPaged (e o FindPage) não contar os documentos: Total vem como -1
+// (não calculado). A contagem costuma ser a parte cara da paginação; use quando a tela só
+// precisa dos dados da página.
+func WithoutTotals() Option {
+	return func(c *config) { c.noTotals = true }
+}
+
+// WithDefaultSort define a ordenação usada por Find, FindAll, FindAs e FindPaged quando o
+// chamador não informa uma (no FindPaged, sort nil ou vazio). Uma ordenação explícita
+// sempre substitui a padrão.
+//
+// Exemplo de uso:
+//
+//	posts := monger.New[Post](db, "posts", monger.WithDefaultSort(monger.D{{Key: "createdAt", Value: -1}}))
+func WithDefaultSort(sort D) Option {
+	return func(c *config) { c.defaultSort = sort }
+}
+
+// WithCollation define a collation das consultas do repositório (Find, FindOne, FindAll,
+// FindAs, FindOneAs, FindPaged, FindPage, FindPageHasMore, Count, Exists): regras de
+// comparação de strings sensíveis ao idioma, como ordenar "é" junto de "e" ou igualdade sem
+// diferenciar maiúsculas (Strength 1 ou 2). No FindPaged, vale para a busca e para a contagem. Use UseCollation no
+// ctx para trocá-la em uma chamada.
+//
+// Um índice só é usado por consultas com a mesma collation dele: crie os índices dos campos
+// ordenados/filtrados com a mesma collation (IndexCollation).
+//
+// Exemplo de uso:
+//
+//	users := monger.New[User](db, "users", monger.WithCollation(&options.Collation{Locale: "pt", Strength: 1}))
+func WithCollation(c *options.Collation) Option {
+	return func(cfg *config) { cfg.collation = c }
+}
+
+// WithRowSecurity registra um provedor de filtro de segurança por linha: em toda leitura
+// do repositório, o filtro retornado por fn (derivado do ctx da requisição, ex.: os escopos
+// do usuário) é combinado com $and ao filtro do chamador, e não pode ser contornado por ele.
+//
+// Se fn retornar erro (ex.: usuário sem escopo), a leitura falha com esse erro em vez de
+// retornar dados sem restrição. Se fn retornar nil sem erro, a leitura não é restringida
+// (ex.: administradores).
+//
+// Exemplo de uso:
+//
+//	orders := monger.New[Order](db, "orders", monger.WithRowSecurity(func(ctx context.Context) (*monger.FilterBuilder, error) {
+//	    region, ok := RegionFromContext(ctx)
+//	    if !ok {
+//	        return nil, errors.New("usuário sem região")
+//	    }
+//	    return monger.Filter().Eq("region", region), nil
+//	}))
+func WithRowSecurity(fn func(ctx context.Context) (*FilterBuilder, error)) Option {
+	return func(c *config) { c.rowSecurity = fn }
+}
+
+// WithSoftDelete habilita a exclusão lógica: DeleteByID passa a gravar {field: time.Now()}
+// em vez de remover o documento, e as leituras passam a ignorar documentos com o campo
+// preenchido (são considerados ativos os documentos em que o campo está ausente ou nulo).
+//
+// No model, declare o campo como ponteiro com omitempty, para que documentos ativos não
+// gravem uma data zerada:
+//
+//	DeletedAt *time.Time `bson:"deletedAt,omitempty"`
+func WithSoftDelete(field string) Option {
+	return func(c *config) { c.softDeleteField = field }
+}
+
+// WithVersioning habilita o controle de concorrência otimista usando field como número de
+// versão do documento (ex.: "version"). As inserções começam na versão 1 e toda atualização
+// (inclusive o soft-delete e o Restore) incrementa o campo. Transform e os updates que trazem
+// a versão esperada (UpdateByID com o campo preenchido no patch) só são aplicados se a versão
+// não mudou desde a leitura; caso contrário, retornam ErrVersionConflict.
+//
+// Documentos sem o campo são tratados como versão 0. No model e nos patches:
+//
+//	Version int64 `bson:"version"`            // model
+//	Version *int64 `bson:"version,omitempty"` // patch: versão esperada (If-Match)
+func WithVersioning(field string) Option {
+	return func(c *config) { c.versionField = field }
+}
+
+// WithTimestamps habilita datas automáticas: nas inserções, createdField recebe time.Now()
+// (se ainda não preenchido) e updatedField também; nas atualizações (UpdateByID, UpdateMany,
+// MergePatchByID, Transform, upsert e soft-delete), updatedField recebe time.Now().
+// Qualquer um dos campos pode ser "" para desabilitá-lo.
+//
+// Os campos são reconhecidos pelas tags bson do model: um campo que T não declara é ignorado,
+// então a opção é segura para models sem timestamps (models M ou com mapa ",inline" aceitam
+// qualquer campo). Declare-os como time.Time (ou *time.Time) no model.
+//
+// Com updatedField indexado, ChangedSince permite sincronização incremental (delta sync).
+//
+// Exemplo de uso:
+//
+//	users := monger.New[User](db, "users", monger.WithTimestamps("createdAt", "updatedAt"))
+func WithTimestamps(createdField, updatedField string) Option {
+	return func(c *config) {
+		c.createdField = createdField
+		c.updatedField = updatedField
+	}
+}
+
+// WithSchemaValidation aplica GenerateSchema[T] como validador ($jsonSchema) da coleção na
+// primeira escrita do repositório, criando a coleção se necessário. A validação passa a ser
+// feita também pelo servidor, inclusive para escritas que não passam pelo Monger.
+//
+// Requer permissão para collMod/createCollection. Revise o schema gerado com
+// GenerateSchema antes de habilitar em coleções com dados existentes.
+func WithSchemaValidation() Option {
+	return func(c *config) { c.schemaValidation = true }
+}
+
+// WithImmutableFields marca campos (nomes bson, ex.: "ownerId", "createdAt") como imutáveis:
+// são gravados na criação, mas removidos silenciosamente do $set das atualizações parciais
+// (UpdateByID, UpdateByIDs, UpdateMany, MergePatchByID); no upsert (InsertOneAndUpdate) vão
+// para $setOnInsert e no Transform mantêm o valor original. Protege contra mass-assignment.
+//
+// O mesmo efeito pode ser declarado no model com a tag `monger:"immutable"`:
+//
+//	OwnerID primitive.ObjectID `bson:"ownerId" monger:"immutable"`
+func WithImmutableFields(fields ...string) Option {
+	return func(c *config) { c.immutableFields = append(c.immutableFields, fields...) }
+}
+
+// WithRejectImmutable faz as atualizações parciais falharem com ErrImmutableField quando
+// tentam alterar um campo imutável, em vez de apenas ignorá-lo.
+func WithRejectImmutable() Option {
+	return func(c *config) { c.rejectImmutable = true }
+}
+
+// WithDottedMaps faz as atualizações parciais gravarem mapas com chaves string (ex.:
+// Metadata map[string]any) chave a chave, com caminhos pontuados ("metadata.foo"), em vez de
+// substituir o mapa inteiro; as chaves que já existem no documento e não vieram no mapa são
+// preservadas. Um mapa vazio (não nil) continua gravando {} e limpa o campo; um mapa nil é
+// ignorado. A tag `monger:"replace"` no campo mantém a substituição inteira.
+func WithDottedMaps() Option {
+	return func(c *config) { c.dottedMaps = true }
+}
+
+// WithTagPriority define de quais tags de struct as atualizações parciais leem os nomes dos
+// campos, em ordem: vale a primeira presente no campo (ex.: "bson", "json" usa a tag json
+// quando não há bson). Sem nenhuma das tags, o nome é o do campo em minúsculas, como no driver.
+// Padrão: apenas "bson".
+//
+// Os nomes gravados precisam coincidir com os do documento: use junto com um registry do
+// driver que também leia a tag json (ex.: bsoncodec.JSONFallbackStructTagParser).
+func WithTagPriority(tags ...string) Option {
+	return func(c *config) { c.tagPriority = tags }
+}
+
+// WithWriteZeroFields faz as atualizações parciais gravarem também os campos não ponteiro
+// zerados (0, "", false), exceto os marcados com omitempty na tag — a mesma regra do driver
+// ao gravar o documento inteiro. Ponteiros nil continuam ignorados. Os campos mantidos pelo
+// repositório (versão, datas de WithTimestamps e soft-delete) nunca são gravados zerados.
+func WithWriteZeroFields() Option {
+	return func(c *config) { c.writeZeros = true }
+}
+
+// WithExplainWarnings habilita verificações de desenvolvimento que avisam sobre consultas que
+// tendem a ficar lentas em produção. Hoje: ordenações (Find, FindAll, FindAs, FindPaged,
+// FindPageHasMore) que nenhum índice da coleção suporta, forçando ordenação em memória.
+//
+// Os avisos são enviados para warn, uma vez por ordenação. Com warn nil, vão para o logger de
+// WithLogger (operação "Warning") ou, sem logger, para o log padrão. A lista de índices é
+// consultada com ListIndexes e mantida em cache por um minuto. Recomendado apenas em
+// desenvolvimento/testes.
+//
+// Exemplo de uso:
+//
+//	users := monger.New[User](db, "users", monger.WithExplainWarnings(func(msg string) {
+//	    slog.Warn(msg)
+//	}))
+func WithExplainWarnings(warn func(msg string)) Option {
+	return func(c *config) {
+		c.explainWarnings = true
+		c.warn = warn
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: patch.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o suporte a JSON Merge Patch (RFC 7396): traduz o
+	corpo de um HTTP PATCH em operações $set/$unset com caminhos pontuados.
+*/
+package monger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MergePatchByID aplica um JSON Merge Patch (RFC 7396) ao documento com o ID informado.
+//
+// Semântica:
+//   - valores não nulos são gravados com $set;
+//   - null remove o campo ($unset);
+//   - objetos aninhados são mesclados recursivamente, usando caminhos pontuados
+//     (ex.: {"address": {"city": "Recife"}} vira $set {"address.city": "Recife"});
+//   - arrays e valores simples substituem o valor atual por inteiro.
+//
+// O patch precisa ser um objeto JSON válido; o campo _id não pode ser alterado e chaves
+// com "." ou iniciadas por "$" são rejeitadas. Campos imutáveis (WithImmutableFields) são
+// ignorados. Um patch sem operações não altera nada.
+//
+// Observação: diferente da RFC, se o valor atual de um campo não for um objeto, mesclar um
+// objeto nele falha no servidor (o MongoDB não cria caminhos dentro de valores simples).
+//
+// Exemplo de uso:
+//
+//	// PATCH /users/{id}  {"name": "Ana", "address": {"zip": null}}
+//	err := users.MergePatchByID(ctx, id, body)
+func (r *Repository[T]) MergePatchByID(ctx context.Context, id string, patch json.RawMessage) (err error) {
+	ctx, span := r.startSpan(ctx, "MergePatchByID")
+	var matched int64
+	defer func() { span.end(err, matched) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if err := r.ensureSchema(ctx); err != nil {
+		return err
+	}
+	oid, err := r.cfg.parseID(id)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(patch))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("merge patch inválido: %w", err)
+	}
+	if dec.More() {
+		return fmt.Errorf("merge patch inválido: conteúdo após o objeto JSON")
+	}
+	if doc == nil {
+		return fmt.Errorf("merge patch inválido: deve ser um objeto JSON")
+	}
+
+	set, unset := M{}, M{}
+	if err := flattenMergePatch(doc, "", set, unset); err != nil {
+		return err
+	}
+	if err := r.stripImmutable(set); err != nil {
+		return err
+	}
+	if err := r.stripImmutable(unset); err != nil {
+		return err
+	}
+
+	if f := r.cfg.versionField; f != "" {
+		delete(set, f)
+		delete(unset, f)
+	}
+	if len(set) == 0 && len(unset) == 0 {
+		return nil
+	}
+	r.touch(set)
+	delete(unset, r.cfg.updatedField)
+	update := r.setUpdate(set)
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	res, err := r.coll.UpdateOne(ctx, M{"_id": oid}, update)
+	if err != nil {
+		return writeError(err)
+	}
+	matched = res.MatchedCount
+	return nil
+}
+
+// flattenMergePatch converte um merge patch em caminhos pontuados de $set e $unset.
+func flattenMergePatch(doc map[string]any, prefix string, set, unset M) error {
+	for key, val := range doc {
+		if key == "" || strings.Contains(key, ".") || strings.HasPrefix(key, "$") {
+			return fmt.Errorf("merge patch inválido: chave %q não permitida", prefix+key)
+		}
+		path := prefix + key
+		if path == "_id" {
+			return fmt.Errorf("merge patch não pode alterar _id")
+		}
+
+		switch v := val.(type) {
+		case nil:
+			unset[path] = ""
+		case map[string]any:
+			if err := flattenMergePatch(v, path+".", set, unset); err != nil {
+				return err
+			}
+		default:
+			set[path] = jsonToBSON(v)
+		}
+	}
+	return nil
+}
+
+// jsonToBSON converte valores decodificados com UseNumber para tipos BSON, mantendo
+// inteiros como int64 (em vez de float64).
+func jsonToBSON(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]any:
+		m := make(M, len(t))
+		for k, item := range t {
+			m[k] = jsonToBSON(item)
+		}
+		return m
+	case []any:
+		items := make([]any, len(t))
+		for i, item := range t {
+			items[i] = jsonToBSON(item)
+		}
+		return items
+	}
+	return v
+}
@@ -0,0 +1,224 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: populate.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o Populate: resolve referências entre coleções
+	(estilo "populate" do Mongoose) buscando todos os documentos referenciados
+	em uma única consulta $in e anexando-os aos models via reflection.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Populate resolve a referência localField de cada documento em docs, buscando na coleção
+// from (do mesmo banco do repositório) os documentos cujo foreignField corresponde, e
+// grava o resultado (decodificado em Ref) no campo targetField de cada documento.
+//
+// Todas as referências são buscadas em uma única consulta {foreignField: {$in: [...]}},
+// evitando o problema de N+1 consultas.
+//
+// Regras:
+//   - localField e targetField podem ser o nome bson ou o nome Go do campo.
+//   - localField pode ser um valor simples (ex.: ObjectID) ou um slice (referência 1:N).
+//   - targetField pode ser do tipo Ref, *Ref ou []Ref. Para Ref/*Ref é usado o primeiro
+//     documento encontrado; []Ref recebe todos.
+//   - Documentos sem referência correspondente mantêm targetField com o valor zero.
+//
+// Exemplo de uso:
+//
+//	type Post struct {
+//	    ID       primitive.ObjectID `bson:"_id,omitempty"`
+//	    AuthorID primitive.ObjectID `bson:"authorId"`
+//	    Author   *Author            `bson:"-"` // preenchido pelo Populate
+//	}
+//
+//	posts, _ := postsRepo.FindAll(ctx, nil, nil, 50)
+//	err := monger.Populate[Author](ctx, postsRepo, posts, "authorId", "users", "_id", "Author")
+func Populate[Ref any, T any](ctx context.Context, r *Repository[T], docs []T, localField, from, foreignField, targetField string) (err error) {
+	ctx, span := r.startSpan(ctx, "Populate")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if localField == "" || from == "" || foreignField == "" || targetField == "" {
+		return fmt.Errorf("localField, from, foreignField e targetField são obrigatórios")
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	t := reflect.TypeOf(docs).Elem()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("Populate requer um slice de structs")
+	}
+	localIdx, ok := structFieldIndex(t, localField)
+	if !ok {
+		return fmt.Errorf("campo %q não encontrado em %s", localField, t.Name())
+	}
+	targetIdx, ok := structFieldIndex(t, targetField)
+	if !ok {
+		return fmt.Errorf("campo %q não encontrado em %s", targetField, t.Name())
+	}
+
+	refType := reflect.TypeOf((*Ref)(nil)).Elem()
+	targetType := t.FieldByIndex(targetIdx).Type
+	switch targetType {
+	case refType, reflect.PointerTo(refType), reflect.SliceOf(refType):
+	default:
+		return fmt.Errorf("campo %q deve ser %s, *%s ou []%s", targetField, refType, refType, refType)
+	}
+
+	// Coleta os valores referenciados (sem repetição)
+	values := []any{}
+	seen := map[any]bool{}
+	docsVal := reflect.ValueOf(docs)
+	for i := 0; i < docsVal.Len(); i++ {
+		for _, v := range refValues(docsVal.Index(i).FieldByIndex(localIdx)) {
+			key := refKey(v)
+			if !seen[key] {
+				seen[key] = true
+				values = append(values, v)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	// Busca todas as referências de uma vez
+	cursor, err := r.coll.Database().Collection(from).Find(ctx, M{foreignField: M{"$in": values}})
+	if err != nil {
+		return fmt.Errorf("erro ao buscar referências em %s: %w", from, err)
+	}
+	defer cursor.Close(ctx)
+
+	byKey := map[any][]Ref{}
+	for cursor.Next(ctx) {
+		var ref Ref
+		if err := cursor.Decode(&ref); err != nil {
+			return fmt.Errorf("erro ao decodificar referência: %w", err)
+		}
+		fv, err := cursor.Current.LookupErr(strings.Split(foreignField, ".")...)
+		if err != nil {
+			continue
+		}
+		var foreign any
+		if err := fv.Unmarshal(&foreign); err != nil {
+			continue
+		}
+		key := refKey(foreign)
+		byKey[key] = append(byKey[key], ref)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	// Anexa as referências em cada documento
+	for i := 0; i < docsVal.Len(); i++ {
+		doc := docsVal.Index(i)
+		var matches []Ref
+		for _, v := range refValues(doc.FieldByIndex(localIdx)) {
+			matches = append(matches, byKey[refKey(v)]...)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		target := doc.FieldByIndex(targetIdx)
+		switch targetType.Kind() {
+		case reflect.Slice:
+			target.Set(reflect.ValueOf(matches))
+		case reflect.Pointer:
+			ref := matches[0]
+			target.Set(reflect.ValueOf(&ref))
+		default:
+			target.Set(reflect.ValueOf(matches[0]))
+		}
+	}
+	return nil
+}
+
+// structFieldIndex localiza um campo pelo nome bson ou pelo nome Go, incluindo campos
+// de structs embutidas/inline.
+func structFieldIndex(t reflect.Type, name string) ([]int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag, inline := parseBsonTag(sf.Tag.Get("bson"))
+		if sf.Name == name || (tag != "" && tag != "-" && tag == name) {
+			return []int{i}, true
+		}
+		if (inline || sf.Anonymous) && sf.Type.Kind() == reflect.Struct {
+			if sub, ok := structFieldIndex(sf.Type, name); ok {
+				return append([]int{i}, sub...), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// refValues extrai os valores de referência de um campo (valor simples, ponteiro ou slice).
+func refValues(v reflect.Value) []any {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		values := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, refValues(v.Index(i))...)
+		}
+		return values
+	}
+	if v.IsZero() {
+		return nil
+	}
+	return []any{v.Interface()}
+}
+
+// refKey normaliza um valor para uso como chave de mapa, de forma que o valor do model Go
+// e o valor decodificado do BSON coincidam (ex.: int no Go vs int32/int64 no BSON).
+func refKey(v any) any {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f == float64(int64(f)) {
+			return int64(f)
+		}
+		return f
+	case reflect.String:
+		return rv.String()
+	}
+	if v == nil || !rv.Type().Comparable() {
+		raw, _ := bson.Marshal(M{"v": v})
+		return string(raw)
+	}
+	return v
+}
@@ -0,0 +1,137 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: retry.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define a retentativa com backoff exponencial (WithRetry)
+	para erros transitórios, como troca de primário e falhas de rede.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WithRetry reexecuta as operações que falham com erro transitório (erro de rede, rótulo
+// RetryableWriteError/TransientTransactionError ou códigos como PrimarySteppedDown e
+// NotWritablePrimary), até attempts tentativas no total, esperando entre elas um backoff
+// exponencial com jitter: ~backoff, ~2×backoff, ~4×backoff, ...
+//
+// Só são retentadas as operações seguras de repetir:
+//   - leituras (Find, FindOne, FindByID, FindByIDs, FindAll, FindAs, FindOneAs, FindPaged,
+//     FindPage, Count, Exists e a abertura do cursor das agregações, exceto as que terminam
+//     em $out ou $merge);
+//   - updates que só gravam valores ($set/$unset de UpdateByID, UpdateByIDPatch e UpdateMany)
+//     quando não há WithVersioning (o $inc da versão não é idempotente);
+//   - remoções físicas (DeleteByID e DeleteMany sem WithSoftDelete).
+//
+// Inserções, upserts, FindOneAndUpdate e updates com operadores não são repetidos aqui:
+// contam apenas com as retryable writes do driver, que evitam duplicidade.
+//
+// A espera respeita o ctx (cancelamento e prazo interrompem as retentativas) e, dentro de
+// uma sessão (WithTransaction, Snapshot), não há retentativa: a transação decide.
+//
+// Exemplo de uso:
+//
+//	users := monger.New[User](db, "users", monger.WithRetry(4, 100*time.Millisecond))
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(c *config) {
+		c.retryAttempts = attempts
+		c.retryBackoff = backoff
+	}
+}
+
+// withRetry executa fn e, com WithRetry, a reexecuta enquanto o erro for transitório.
+// idempotent false desliga a retentativa (a operação não é segura de repetir).
+func withRetry[R any](ctx context.Context, c *config, idempotent bool, fn func() (R, error)) (R, error) {
+	res, err := fn()
+	if !idempotent || c.retryAttempts <= 1 || mongo.SessionFromContext(ctx) != nil {
+		return res, err
+	}
+	for attempt := 1; attempt < c.retryAttempts && isTransientError(err); attempt++ {
+		if waitErr := sleepBackoff(ctx, c.retryBackoff, attempt); waitErr != nil {
+			return res, err
+		}
+		res, err = fn()
+	}
+	return res, err
+}
+
+// sleepBackoff aguarda o backoff da tentativa (backoff × 2^(attempt-1), com jitter entre a
+// metade e o valor cheio), ou retorna o erro do ctx se ele terminar antes.
+func sleepBackoff(ctx context.Context, backoff time.Duration, attempt int) error {
+	d := backoff << (attempt - 1)
+	if d <= 0 {
+		d = backoff
+	}
+	if d > 0 {
+		d = d/2 + rand.N(d/2+1)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// isTransientError indica se err é um erro transitório do servidor ou da rede, em que a
+// mesma operação pode ter sucesso se reexecutada. Cancelamento e prazo do ctx não são.
+func isTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) &&
+		(labeled.HasErrorLabel("RetryableWriteError") || labeled.HasErrorLabel("TransientTransactionError")) {
+		return true
+	}
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return isTransientWriteCode(int(cmdErr.Code))
+	}
+	return false
+}
+
+// find abre o cursor de uma consulta, com retentativa (WithRetry).
+func (r *Repository[T]) find(ctx context.Context, filter any, opts *options.FindOptions) (*mongo.Cursor, error) {
+	return withRetry(ctx, &r.cfg, true, func() (*mongo.Cursor, error) {
+		return r.coll.Find(ctx, filter, opts)
+	})
+}
+
+// findOne busca e decodifica um documento, com retentativa (WithRetry).
+func (r *Repository[T]) findOne(ctx context.Context, filter any, opts *options.FindOneOptions) (*T, error) {
+	return withRetry(ctx, &r.cfg, true, func() (*T, error) {
+		return decodeSingle[T](ctx, r.coll.FindOne(ctx, filter, opts), r.coll.Name())
+	})
+}
+
+// countDocuments conta os documentos do filtro, com retentativa (WithRetry).
+func (r *Repository[T]) countDocuments(ctx context.Context, filter any, opts *options.CountOptions) (int64, error) {
+	return withRetry(ctx, &r.cfg, true, func() (int64, error) {
+		return r.coll.CountDocuments(ctx, filter, opts)
+	})
+}
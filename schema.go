@@ -0,0 +1,245 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: schema.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define a geração de $jsonSchema a partir do model (tags bson
+	e tipos Go) e sua aplicação como validador da coleção (WithSchemaValidation).
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// schemaState controla a aplicação preguiçosa do validador (uma vez por repositório).
+type schemaState struct {
+	mu      sync.Mutex
+	applied bool
+}
+
+// GenerateSchema gera um $jsonSchema a partir das tags bson e dos tipos de T, para ser
+// revisado ou aplicado como validador da coleção (veja WithSchemaValidation e ApplySchema).
+//
+// Regras:
+//   - campos sem omitempty são obrigatórios (required);
+//   - bsonType vem do tipo Go (string, bool, int/long, double, date, objectId, array, object, ...);
+//   - ponteiros, slices e mapas aceitam também null (é como o driver grava valores nil);
+//   - structs aninhados geram sub-schemas; structs ",inline" têm os campos incorporados;
+//   - campos extras (não declarados em T) continuam permitidos.
+//
+// Exemplo de uso:
+//
+//	schema := monger.GenerateSchema[User]()
+//	b, _ := json.MarshalIndent(schema, "", "  ")
+//	fmt.Println(string(b))
+func GenerateSchema[T any]() M {
+	return structSchema(reflect.TypeOf((*T)(nil)).Elem(), map[reflect.Type]bool{})
+}
+
+// ApplySchema aplica GenerateSchema[T] como validador da coleção: atualiza o validador com
+// collMod ou, se a coleção ainda não existir, a cria já com o validador. Com
+// WithSchemaValidation isso é feito automaticamente na primeira escrita; chame diretamente
+// para aplicar na inicialização (ex.: em migrações).
+func (r *Repository[T]) ApplySchema(ctx context.Context) (err error) {
+	ctx, span := r.startSpan(ctx, "ApplySchema")
+	defer func() { span.end(err, -1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	validator := M{"$jsonSchema": GenerateSchema[T]()}
+	db := r.coll.Database()
+
+	err = db.RunCommand(ctx, D{
+		{Key: "collMod", Value: r.coll.Name()},
+		{Key: "validator", Value: validator},
+	}).Err()
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == 26 { // NamespaceNotFound
+		return db.CreateCollection(ctx, r.coll.Name(), options.CreateCollection().SetValidator(validator))
+	}
+	return err
+}
+
+// ensureSchema aplica o validador na primeira escrita quando WithSchemaValidation está
+// habilitado. Em caso de erro, a aplicação é tentada de novo na próxima escrita.
+func (r *Repository[T]) ensureSchema(ctx context.Context) error {
+	if r.schema == nil {
+		return nil
+	}
+	r.schema.mu.Lock()
+	defer r.schema.mu.Unlock()
+	if r.schema.applied {
+		return nil
+	}
+	// collMod não pode rodar dentro de uma transação: aplica fora da sessão
+	ctx, cancel := withoutSession(ctx)
+	defer cancel()
+	if err := r.ApplySchema(ctx); err != nil {
+		return err
+	}
+	r.schema.applied = true
+	return nil
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	objectIDType  = reflect.TypeOf(primitive.ObjectID{})
+	dateTimeType  = reflect.TypeOf(primitive.DateTime(0))
+	decimalType   = reflect.TypeOf(primitive.Decimal128{})
+	timestampType = reflect.TypeOf(primitive.Timestamp{})
+	binaryType    = reflect.TypeOf(primitive.Binary{})
+	regexType     = reflect.TypeOf(primitive.Regex{})
+)
+
+// structSchema gera o schema (bsonType object) de um struct. Tipos recursivos (ex.: árvores)
+// são descritos apenas como object a partir da segunda ocorrência.
+func structSchema(t reflect.Type, seen map[reflect.Type]bool) M {
+	if seen[t] {
+		return M{"bsonType": "object"}
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	properties := M{}
+	required := []string{}
+	collectSchemaFields(t, properties, &required, seen)
+
+	schema := M{"bsonType": "object"}
+	if len(properties) > 0 {
+		schema["properties"] = properties
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// collectSchemaFields registra as propriedades (e os obrigatórios) dos campos de t.
+func collectSchemaFields(t reflect.Type, properties M, required *[]string, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := sf.Tag.Get("bson")
+		name, inline := parseBsonTag(tag)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			if ft := derefType(sf.Type); ft.Kind() == reflect.Struct {
+				collectSchemaFields(ft, properties, required, seen)
+			}
+			continue // mapas inline: campos arbitrários, nada a declarar
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		if schema := typeSchema(sf.Type, seen); schema != nil {
+			properties[name] = schema
+		}
+		if !strings.Contains(tag, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// typeSchema gera o schema de um tipo Go, ou nil quando qualquer valor é aceito (interface).
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) M {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		nullable = true
+		t = t.Elem()
+	}
+
+	var schema M
+	switch {
+	case t == timeType || t == dateTimeType:
+		schema = M{"bsonType": "date"}
+	case t == objectIDType:
+		schema = M{"bsonType": "objectId"}
+	case t == decimalType:
+		schema = M{"bsonType": "decimal"}
+	case t == timestampType:
+		schema = M{"bsonType": "timestamp"}
+	case t == binaryType:
+		schema = M{"bsonType": "binData"}
+	case t == regexType:
+		schema = M{"bsonType": "regex"}
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			schema = M{"bsonType": "string"}
+		case reflect.Bool:
+			schema = M{"bsonType": "bool"}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// o driver grava inteiros como int32 quando cabem e int64 caso contrário
+			schema = M{"bsonType": []string{"int", "long"}}
+		case reflect.Float32, reflect.Float64:
+			schema = M{"bsonType": "double"}
+		case reflect.Slice, reflect.Array:
+			nullable = nullable || t.Kind() == reflect.Slice
+			if t.Elem().Kind() == reflect.Uint8 {
+				schema = M{"bsonType": "binData"}
+				break
+			}
+			schema = M{"bsonType": "array"}
+			if items := typeSchema(t.Elem(), seen); items != nil {
+				schema["items"] = items
+			}
+		case reflect.Map:
+			nullable = true
+			schema = M{"bsonType": "object"}
+		case reflect.Struct:
+			schema = structSchema(t, seen)
+		default:
+			return nil
+		}
+	}
+
+	if nullable {
+		schema["bsonType"] = appendNull(schema["bsonType"])
+	}
+	return schema
+}
+
+// appendNull acrescenta "null" aos tipos aceitos de um bsonType.
+func appendNull(bsonType any) []string {
+	switch v := bsonType.(type) {
+	case string:
+		return []string{v, "null"}
+	case []string:
+		return append(append([]string{}, v...), "null")
+	}
+	return []string{"null"}
+}
+
+// derefType remove os níveis de ponteiro de um tipo.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,124 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: session.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define utilitários de sessão do MongoDB, como leituras
+	consistentes a partir de um mesmo snapshot e transações.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Snapshot representa uma sessão de leitura com read concern "snapshot".
+// Todas as leituras feitas com Context() enxergam o mesmo ponto no tempo,
+// mesmo que haja escritas concorrentes entre elas.
+type Snapshot struct {
+	sess mongo.Session
+	ctx  context.Context
+}
+
+// Snapshot inicia uma sessão de snapshot no cliente do repositório e retorna um handle
+// cujo contexto deve ser passado às leituras que precisam ser consistentes entre si.
+// O handle funciona para qualquer Repository do mesmo cliente.
+//
+// O ponto no tempo é fixado na primeira leitura da sessão. O servidor mantém o histórico
+// do snapshot por tempo limitado (minSnapshotHistoryWindowInSeconds, 5 minutos por
+// padrão): leituras feitas depois disso falham com SnapshotTooOld. Requer replica set
+// ou cluster sharded (MongoDB 5.0+).
+//
+// Exemplo de uso:
+//
+//	snap, err := orders.Snapshot(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	defer snap.End()
+//
+//	total, _ := orders.Count(snap.Context(), nil)
+//	paid, _ := orders.Count(snap.Context(), monger.Filter().Eq("status", "paid"))
+func (r *Repository[T]) Snapshot(ctx context.Context) (*Snapshot, error) {
+	sess, err := r.coll.Database().Client().StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{sess: sess, ctx: mongo.NewSessionContext(ctx, sess)}, nil
+}
+
+// Context retorna o contexto que faz as operações usarem a sessão de snapshot.
+func (s *Snapshot) Context() context.Context {
+	return s.ctx
+}
+
+// End encerra a sessão de snapshot. Deve ser chamado ao final das leituras.
+func (s *Snapshot) End() {
+	s.sess.EndSession(context.Background())
+}
+
+// WithTransaction executa fn dentro de uma transação: inicia uma sessão, chama fn com o
+// contexto da sessão e faz commit se fn retornar nil (ou abort se retornar erro).
+//
+// Todos os métodos dos repositórios que recebem sessCtx participam da transação, inclusive
+// em coleções diferentes do mesmo cliente. Segue o padrão recomendado pelo driver: a
+// transação inteira é reexecutada em erros com o label TransientTransactionError, e o commit
+// é repetido com UnknownTransactionCommitResult (até 120 segundos no total). Por isso fn pode
+// rodar mais de uma vez e não deve ter efeitos colaterais fora do banco.
+//
+// Requer replica set ou cluster sharded: standalones não suportam transações.
+//
+// Exemplo de uso:
+//
+//	err := monger.WithTransaction(ctx, client, func(sessCtx mongo.SessionContext) error {
+//	    if _, err := accounts.UpdateByID(sessCtx, from, &AccountPatch{Balance: monger.Value(fromBalance - amount)}); err != nil {
+//	        return err
+//	    }
+//	    _, err := accounts.UpdateByID(sessCtx, to, &AccountPatch{Balance: monger.Value(toBalance + amount)})
+//	    return err
+//	})
+func WithTransaction(ctx context.Context, client *mongo.Client, fn func(sessCtx mongo.SessionContext) error) error {
+	if client == nil {
+		return fmt.Errorf("client não pode ser nil")
+	}
+	if fn == nil {
+		return fmt.Errorf("fn não pode ser nil")
+	}
+	sess, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(context.Background())
+
+	_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// withoutSession retorna um contexto sem a sessão de ctx (mantendo o prazo), para comandos
+// que não podem rodar dentro de uma transação (ex.: collMod).
+func withoutSession(ctx context.Context) (context.Context, context.CancelFunc) {
+	if mongo.SessionFromContext(ctx) == nil {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(context.Background(), deadline)
+	}
+	return context.WithCancel(context.Background())
+}
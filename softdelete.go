@@ -0,0 +1,242 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: softdelete.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o soft-delete (exclusão lógica): em vez de remover o
+	documento, o repositório grava a data de exclusão em um campo e passa a
+	ignorá-lo nas leituras. Também define a cascata de soft-delete para
+	coleções filhas.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OnSoftDelete registra uma função chamada depois que um documento é marcado como excluído
+// (soft-delete), recebendo o _id (hex) do documento. Use para limpar dados relacionados.
+// Se fn retornar erro, a operação de exclusão retorna esse erro.
+//
+// A função recebe o mesmo ctx da exclusão: se a exclusão for feita dentro de uma transação
+// (ctx com sessão), as escritas da função participam da mesma transação. Fora de uma
+// transação, a marcação do pai e as funções são executadas em sequência, sem atomicidade.
+//
+// Deve ser chamado na inicialização (não é seguro registrar concorrentemente com operações).
+func (r *Repository[T]) OnSoftDelete(fn func(ctx context.Context, deletedID string) error) {
+	r.cfg.onSoftDelete = append(r.cfg.onSoftDelete, fn)
+}
+
+// Restore desfaz o soft-delete do documento com o ID informado, removendo o campo de
+// exclusão. Retorna ErrNotFound se o documento não existir ou não estiver excluído.
+// Requer WithSoftDelete. As cascatas (OnSoftDelete, WithCascade) não são desfeitas.
+//
+// Exemplo de uso:
+//
+//	err := users.Restore(ctx, id)
+func (r *Repository[T]) Restore(ctx context.Context, id string) (err error) {
+	ctx, span := r.startSpan(ctx, "Restore")
+	defer func() { span.end(err, 1) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	field := r.cfg.softDeleteField
+	if field == "" {
+		return fmt.Errorf("Restore requer WithSoftDelete")
+	}
+	oid, err := r.cfg.parseID(id)
+	if err != nil {
+		return err
+	}
+	filter, err := r.securityFilter(ctx, M{"_id": oid, field: M{"$ne": nil}})
+	if err != nil {
+		return err
+	}
+	set := M{}
+	r.touch(set)
+	update := r.setUpdate(set)
+	update["$unset"] = M{field: ""}
+	res, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return writeError(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// FindDeleted busca apenas os documentos marcados como excluídos que satisfazem o filtro
+// (nil considera todos), com projeção opcional. Requer WithSoftDelete. Para incluir os
+// excluídos junto com os demais em qualquer leitura, use IncludeDeleted.
+//
+// Exemplo de uso:
+//
+//	trash, err := users.FindDeleted(ctx, nil, monger.Select("name", "deletedAt"))
+func (r *Repository[T]) FindDeleted(ctx context.Context, f *FilterBuilder, p *ProjectBuilder) (out []T, err error) {
+	ctx, span := r.startSpan(ctx, "FindDeleted")
+	defer func() { span.end(err, int64(len(out))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	field := r.cfg.softDeleteField
+	if field == "" {
+		return nil, fmt.Errorf("FindDeleted requer WithSoftDelete")
+	}
+	filter, opts := r.getOpts(f, p)
+	filter, err = r.securityFilter(ctx, andFilters(filter, M{field: M{"$ne": nil}}))
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	return decodeCursor[T](ctx, cursor, r.coll.Name())
+}
+
+// softDeleteFilter retorna o filtro que exclui documentos marcados como excluídos
+// (campo ausente ou nulo), ou nil se o soft-delete não estiver habilitado.
+func (r *Repository[T]) softDeleteFilter() M {
+	if r.cfg.softDeleteField == "" {
+		return nil
+	}
+	return M{r.cfg.softDeleteField: nil}
+}
+
+// softDelete marca como excluídos os documentos (ainda não excluídos) que satisfazem o filtro
+// e executa as funções de OnSoftDelete para cada um. Retorna quantos foram marcados.
+func (r *Repository[T]) softDelete(ctx context.Context, filter M) (int64, error) {
+	filter = andFilters(filter, r.softDeleteFilter())
+	set := M{r.cfg.softDeleteField: time.Now()}
+	r.touch(set)
+	update := r.setUpdate(set)
+
+	if len(r.cfg.onSoftDelete) == 0 {
+		res, err := r.coll.UpdateMany(ctx, filter, update)
+		if err != nil {
+			return 0, writeError(err)
+		}
+		return res.ModifiedCount, nil
+	}
+
+	// Com funções de cascata, é preciso saber quais documentos foram marcados
+	cursor, err := r.coll.Find(ctx, filter, options.Find().SetProjection(M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var docs []struct {
+		ID any `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	ids := make([]any, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+	}
+
+	res, err := r.coll.UpdateMany(ctx, andFilters(M{"_id": M{"$in": ids}}, r.softDeleteFilter()), update)
+	if err != nil {
+		return 0, writeError(err)
+	}
+	for _, id := range ids {
+		hexID := r.cfg.idString(id)
+		for _, fn := range r.cfg.onSoftDelete {
+			if err := fn(ctx, hexID); err != nil {
+				return res.ModifiedCount, fmt.Errorf("cascata de soft-delete do documento %s: %w", hexID, err)
+			}
+		}
+	}
+	return res.ModifiedCount, nil
+}
+
+// softDeleteOne marca como excluído o primeiro documento do filtro (já com o escopo de
+// leitura), na ordem de sort, e executa as funções de OnSoftDelete para ele. O resultado traz
+// o documento como estava antes da marcação, com a projeção p (com cascatas, sempre com _id).
+func (r *Repository[T]) softDeleteOne(ctx context.Context, filter M, p *ProjectBuilder, sort D) (*mongo.SingleResult, error) {
+	set := M{r.cfg.softDeleteField: time.Now()}
+	r.touch(set)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if sort != nil {
+		opts.SetSort(sort)
+	}
+	if p != nil {
+		projection := M{}
+		for k, v := range p.Build() {
+			projection[k] = v
+		}
+		if len(r.cfg.onSoftDelete) > 0 {
+			projection["_id"] = 1 // a cascata precisa do _id
+		}
+		opts.SetProjection(projection)
+	}
+	res := r.coll.FindOneAndUpdate(ctx, andFilters(filter, r.softDeleteFilter()), r.setUpdate(set), opts)
+	if len(r.cfg.onSoftDelete) == 0 {
+		return res, nil
+	}
+
+	raw, err := res.Raw()
+	if err != nil {
+		return res, nil // ErrNoDocuments e afins são tratados por quem chama
+	}
+	var id any
+	if err := raw.Lookup("_id").Unmarshal(&id); err != nil {
+		return nil, err
+	}
+	hexID := r.cfg.idString(id)
+	for _, fn := range r.cfg.onSoftDelete {
+		if err := fn(ctx, hexID); err != nil {
+			return nil, fmt.Errorf("cascata de soft-delete do documento %s: %w", hexID, err)
+		}
+	}
+	return res, nil
+}
+
+// WithCascade propaga o soft-delete para uma coleção filha: quando um documento do
+// repositório é excluído, os documentos de child cujo foreignField referencia o _id
+// excluído também são marcados como excluídos (e disparam as cascatas do próprio child).
+//
+// O repositório child precisa ter WithSoftDelete habilitado. As garantias transacionais
+// são as mesmas de OnSoftDelete.
+//
+// Exemplo de uso:
+//
+//	lineItems := monger.New[LineItem](db, "lineItems", monger.WithSoftDelete("deletedAt"))
+//	orders := monger.New[Order](db, "orders",
+//	    monger.WithSoftDelete("deletedAt"),
+//	    monger.WithCascade(lineItems, "orderId"),
+//	)
+func WithCascade[C any](child *Repository[C], foreignField string) Option {
+	return func(c *config) {
+		c.onSoftDelete = append(c.onSoftDelete, func(ctx context.Context, deletedID string) error {
+			if child.cfg.softDeleteField == "" {
+				return fmt.Errorf("WithCascade: a coleção %s não tem soft-delete habilitado", child.coll.Name())
+			}
+			var ref any = deletedID
+			if id, err := c.parseID(deletedID); err == nil {
+				ref = id // mesmo tipo do _id do pai (WithIDCodec)
+			}
+			_, err := child.softDelete(ctx, M{foreignField: ref})
+			return err
+		})
+	}
+}
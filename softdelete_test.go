@@ -0,0 +1,91 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: softdelete_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes do soft-delete e do Restore (versão e erros de escrita), sobre um
+	servidor simulado.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestSoftDeleteIncrementsVersion(t *testing.T) {
+	type doc struct {
+		ID      primitive.ObjectID `bson:"_id,omitempty"`
+		Version int64              `bson:"version"`
+	}
+	ctx := context.Background()
+	id := primitive.NewObjectID().Hex()
+	opts := []Option{WithSoftDelete("deletedAt"), WithVersioning("version")}
+
+	mockRepo(t, opts, func(t *testing.T, mt *mtest.T, r *Repository[doc]) {
+		checkInc := func(op string, update bson.Raw) {
+			t.Helper()
+			inc, err := update.LookupErr("$inc", "version")
+			if err != nil || inc.AsInt64() != 1 {
+				t.Errorf("%s: update sem $inc da versão: %v", op, update)
+			}
+		}
+
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		if _, err := r.DeleteByID(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		u := sentCommand(t, mt).Lookup("updates", "0", "u").Document()
+		checkInc("DeleteByID", u)
+		if _, err := u.LookupErr("$set", "deletedAt"); err != nil {
+			t.Errorf("DeleteByID: update sem $set do campo de exclusão: %v", u)
+		}
+
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		if err := r.Restore(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		u = sentCommand(t, mt).Lookup("updates", "0", "u").Document()
+		checkInc("Restore", u)
+		if _, err := u.LookupErr("$unset", "deletedAt"); err != nil {
+			t.Errorf("Restore: update sem $unset do campo de exclusão: %v", u)
+		}
+
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		if _, err := r.Bulk().DeleteByID(id).Execute(ctx); err != nil {
+			t.Fatal(err)
+		}
+		checkInc("Bulk().DeleteByID", sentCommand(t, mt).Lookup("updates", "0", "u").Document())
+	})
+}
+
+func TestRestoreDuplicateKey(t *testing.T) {
+	type doc struct {
+		ID    primitive.ObjectID `bson:"_id,omitempty"`
+		Email string             `bson:"email"`
+	}
+	mockRepo(t, []Option{WithSoftDelete("deletedAt")}, func(t *testing.T, mt *mtest.T, r *Repository[doc]) {
+		// Índice único parcial (só ativos): outro documento ativo já usa o email
+		mt.AddMockResponses(writeErrorReply(0, 11000))
+		err := r.Restore(context.Background(), primitive.NewObjectID().Hex())
+		if !errors.Is(err, ErrDuplicateKey) {
+			t.Errorf("err = %v, esperado ErrDuplicateKey", err)
+		}
+	})
+}
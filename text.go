@@ -0,0 +1,72 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: text.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o suporte a busca textual ($text): o filtro, suas
+	opções e a projeção/ordenação por relevância (textScore).
+*/
+package monger
+
+// TextOption configura a busca textual de FilterBuilder.Text.
+type TextOption func(M)
+
+// TextLanguage define o idioma da busca ($language), que controla stemming e stop words
+// (ex.: "portuguese", "english", ou "none" para desabilitar).
+func TextLanguage(lang string) TextOption {
+	return func(m M) { m["$language"] = lang }
+}
+
+// TextCaseSensitive faz a busca diferenciar maiúsculas de minúsculas ($caseSensitive).
+func TextCaseSensitive() TextOption {
+	return func(m M) { m["$caseSensitive"] = true }
+}
+
+// TextDiacriticSensitive faz a busca diferenciar acentos ($diacriticSensitive),
+// ex.: "café" deixa de corresponder a "cafe".
+func TextDiacriticSensitive() TextOption {
+	return func(m M) { m["$diacriticSensitive"] = true }
+}
+
+// Text adiciona uma busca textual: {$text: {$search: search, ...}}. Requer um índice de texto
+// na coleção (só pode haver um por coleção). Palavras são combinadas com OU; use aspas para
+// frases ("\"monger go\"") e "-" para excluir termos.
+//
+// Para obter/ordenar pela relevância, combine com ProjectBuilder.Score e TextScoreSort.
+//
+// Exemplo de uso:
+//
+//	f := monger.Filter().Text("mongodb golang", monger.TextLanguage("portuguese"))
+//	res, err := articles.FindPaged(ctx, f, monger.Select("title").Score("score"), 0, 20, monger.TextScoreSort("score"))
+func (b *FilterBuilder) Text(search string, opts ...TextOption) *FilterBuilder {
+	text := M{"$search": search}
+	for _, opt := range opts {
+		opt(text)
+	}
+	b.f["$text"] = text
+	return b
+}
+
+// Score projeta a relevância da busca textual no campo alias: {alias: {$meta: "textScore"}}.
+// Só tem efeito em consultas com FilterBuilder.Text.
+func (b *ProjectBuilder) Score(alias string) *ProjectBuilder {
+	b.p[alias] = M{"$meta": "textScore"}
+	return b
+}
+
+// TextScoreSort retorna a ordenação por relevância (maior primeiro) da busca textual:
+// {alias: {$meta: "textScore"}}. Use o mesmo alias de ProjectBuilder.Score.
+func TextScoreSort(alias string) D {
+	return D{{Key: alias, Value: M{"$meta": "textScore"}}}
+}
@@ -0,0 +1,212 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: timestamps.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define as datas automáticas de criação/atualização
+	(WithTimestamps) e as consultas de sincronização incremental (delta sync).
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// declaresField indica se o model declara o campo (nome bson de primeiro nível, incluindo
+// structs ",inline"). Models que não são structs ou que têm um mapa ",inline" aceitam
+// qualquer campo.
+func declaresField(t reflect.Type, name string) bool {
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag, inline := parseBsonTag(sf.Tag.Get("bson"))
+		if inline {
+			if derefType(sf.Type).Kind() != reflect.Struct || declaresField(sf.Type, name) {
+				return true
+			}
+			continue
+		}
+		if tag == "" {
+			tag = strings.ToLower(sf.Name)
+		}
+		if tag == name {
+			return true
+		}
+	}
+	return false
+}
+
+// touch grava a data de atualização (WithTimestamps) no documento de $set informado.
+func (r *Repository[T]) touch(set M) {
+	if r.cfg.updatedField != "" {
+		set[r.cfg.updatedField] = time.Now()
+	}
+}
+
+// touchUpsert monta o update de um upsert: $set dos campos (com a data de atualização) e
+// $setOnInsert dos campos gravados só na criação (onInsert, mais a data de criação).
+func (r *Repository[T]) touchUpsert(set, onInsert M) M {
+	r.touch(set)
+	if f := r.cfg.createdField; f != "" {
+		_, inSet := set[f]
+		_, inInsert := onInsert[f]
+		if !inSet && !inInsert {
+			onInsert[f] = time.Now()
+		}
+	}
+	update := M{}
+	if f := r.cfg.versionField; f != "" {
+		delete(set, f)
+		delete(onInsert, f)
+		update["$inc"] = M{f: 1} // na inserção, cria a versão 1
+	}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(onInsert) > 0 {
+		update["$setOnInsert"] = onInsert
+	}
+	return update
+}
+
+// insertDocument prepara um model para inserção: chama BeforeInsert e, com WithTimestamps ou
+// WithVersioning, converte o model em documento com as datas de criação (se ainda não
+// preenchida) e de atualização e a versão inicial. Sem essas opções, o model vai como está.
+func (r *Repository[T]) insertDocument(ctx context.Context, model *T) (any, error) {
+	if err := beforeInsert(ctx, model); err != nil {
+		return nil, err
+	}
+	if r.cfg.createdField == "" && r.cfg.updatedField == "" && r.cfg.versionField == "" {
+		return model, nil
+	}
+	doc, err := toDocument(model)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if f := r.cfg.createdField; f != "" && isZeroDate(doc[f]) {
+		doc[f] = now
+	}
+	if f := r.cfg.updatedField; f != "" {
+		doc[f] = now
+	}
+	if f := r.cfg.versionField; f != "" && asInt64(doc[f]) == 0 {
+		doc[f] = int64(1)
+	}
+	return doc, nil
+}
+
+// isZeroDate indica se o valor de um campo de data está ausente ou zerado (time.Time{}).
+func isZeroDate(v any) bool {
+	switch d := v.(type) {
+	case nil:
+		return true
+	case primitive.DateTime:
+		return d.Time().IsZero()
+	case time.Time:
+		return d.IsZero()
+	}
+	return false
+}
+
+// ChangedSince retorna os documentos (não excluídos) criados ou alterados depois de since,
+// em ordem crescente da data de atualização — base para sincronização incremental: o
+// cliente guarda a maior data recebida e a usa como since na próxima chamada.
+//
+// Requer WithTimestamps (com updatedField) e um índice em updatedField. Exclusões não
+// aparecem aqui: com WithSoftDelete, busque-as com DeletedSince para removê-las localmente.
+// Sem soft-delete, exclusões físicas não são detectáveis (exigiria o oplog).
+//
+// Exemplo de uso:
+//
+//	changed, err := notes.ChangedSince(ctx, lastSync, nil)
+//	deleted, err := notes.DeletedSince(ctx, lastSync)
+func (r *Repository[T]) ChangedSince(ctx context.Context, since time.Time, p *ProjectBuilder) (out []T, err error) {
+	ctx, span := r.startSpan(ctx, "ChangedSince")
+	defer func() { span.end(err, int64(len(out))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	field := r.cfg.updatedField
+	if field == "" {
+		return nil, fmt.Errorf("ChangedSince requer WithTimestamps com campo de atualização")
+	}
+	filter, err := r.scopeFilter(ctx, M{field: M{"$gt": since}})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(D{{Key: field, Value: 1}})
+	if p != nil {
+		opts.SetProjection(p.Build())
+	}
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	return decodeCursor[T](ctx, cursor, r.coll.Name())
+}
+
+// DeletedSince retorna os _ids (hex) dos documentos excluídos logicamente depois de since
+// (tombstones), para que clientes de sincronização incremental os removam localmente.
+// WithRowSecurity continua aplicado.
+//
+// Requer WithSoftDelete. Os documentos excluídos permanecem na coleção: ao purgá-los
+// definitivamente, clientes que ainda não sincronizaram deixam de ver a exclusão.
+func (r *Repository[T]) DeletedSince(ctx context.Context, since time.Time) (ids []string, err error) {
+	ctx, span := r.startSpan(ctx, "DeletedSince")
+	defer func() { span.end(err, int64(len(ids))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	field := r.cfg.softDeleteField
+	if field == "" {
+		return nil, fmt.Errorf("DeletedSince requer WithSoftDelete")
+	}
+	filter, err := r.securityFilter(ctx, M{field: M{"$gt": since}})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetProjection(M{"_id": 1}).SetSort(D{{Key: field, Value: 1}})
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID any `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids = make([]string, len(docs))
+	for i, d := range docs {
+		ids[i] = r.cfg.idString(d.ID)
+	}
+	return ids, nil
+}
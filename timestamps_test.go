@@ -0,0 +1,111 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: timestamps_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes das datas automáticas de criação e atualização (WithTimestamps).
+*/
+package monger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+type stamped struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+// namePatch é um update parcial só do nome.
+type namePatch struct {
+	Name string `bson:"name"`
+}
+
+// sentDate lê um campo de data do comando enviado; ok false se ausente ou de outro tipo.
+func sentDate(cmd bson.Raw, path ...string) (time.Time, bool) {
+	v, err := cmd.LookupErr(path...)
+	if err != nil || v.Type != bson.TypeDateTime {
+		return time.Time{}, false
+	}
+	return v.Time(), true
+}
+
+func TestTimestampsOnInsertAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	opts := []Option{WithTimestamps("createdAt", "updatedAt")}
+
+	mockRepo(t, opts, func(t *testing.T, mt *mtest.T, r *Repository[stamped]) {
+		before := time.Now().Add(-time.Second)
+
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 1}))
+		if _, err := r.InsertOne(ctx, &stamped{Name: "a"}); err != nil {
+			t.Fatal(err)
+		}
+		cmd := sentCommand(t, mt)
+		created, ok := sentDate(cmd, "documents", "0", "createdAt")
+		if !ok || created.Before(before) {
+			t.Errorf("createdAt não gravado na inserção: %v", cmd.Lookup("documents", "0"))
+		}
+		if updated, ok := sentDate(cmd, "documents", "0", "updatedAt"); !ok || !updated.Equal(created) {
+			t.Errorf("updatedAt = %v, esperado igual a createdAt %v", updated, created)
+		}
+
+		// createdAt já preenchido é mantido
+		old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 1}))
+		if _, err := r.InsertOne(ctx, &stamped{Name: "b", CreatedAt: old}); err != nil {
+			t.Fatal(err)
+		}
+		if created, _ := sentDate(sentCommand(t, mt), "documents", "0", "createdAt"); !created.Equal(old) {
+			t.Errorf("createdAt = %v, esperado %v (preenchido pelo chamador)", created, old)
+		}
+
+		id := primitive.NewObjectID().Hex()
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		if _, err := r.UpdateByID(ctx, id, &namePatch{Name: "c"}); err != nil {
+			t.Fatal(err)
+		}
+		cmd = sentCommand(t, mt)
+		if updated, ok := sentDate(cmd, "updates", "0", "u", "$set", "updatedAt"); !ok || updated.Before(before) {
+			t.Errorf("updatedAt não atualizado no update: %v", cmd.Lookup("updates", "0", "u"))
+		}
+		if _, err := cmd.LookupErr("updates", "0", "u", "$set", "createdAt"); err == nil {
+			t.Error("update não deveria alterar createdAt")
+		}
+	})
+}
+
+func TestTimestampsIgnoredWhenNotDeclared(t *testing.T) {
+	type plain struct {
+		ID   primitive.ObjectID `bson:"_id,omitempty"`
+		Name string             `bson:"name"`
+	}
+	mockRepo(t, []Option{WithTimestamps("createdAt", "updatedAt")}, func(t *testing.T, mt *mtest.T, r *Repository[plain]) {
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		if _, err := r.UpdateByID(context.Background(), primitive.NewObjectID().Hex(), &namePatch{Name: "x"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sentCommand(t, mt).LookupErr("updates", "0", "u", "$set", "updatedAt"); err == nil {
+			t.Error("updatedAt gravado em um model que não declara o campo")
+		}
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: trace.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o tracing opcional com OpenTelemetry (WithTracer):
+	um span por operação do repositório, com coleção, operação, quantidade
+	de documentos e erros.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracer habilita o tracing das operações do repositório: cada chamada cria um span
+// "monger.<Operação> <coleção>" (ex.: "monger.Find users"), filho do span presente no ctx,
+// com os atributos db.system.name, db.namespace, db.collection.name, db.operation.name e
+// monger.documents (documentos retornados ou afetados). Erros são registrados no span;
+// ErrNotFound não é tratado como erro. Em Iterate, Stream e ForEach*, o span cobre a iteração
+// inteira.
+//
+// Sem WithTracer (padrão), nenhum span é criado.
+//
+// Exemplo de uso:
+//
+//	users := monger.New[User](db, "users", monger.WithTracer(otel.Tracer("myapp/storage")))
+func WithTracer(t trace.Tracer) Option {
+	return func(c *config) { c.tracer = t }
+}
+
+// opSpan é o span de uma operação; nil quando o tracing está desligado.
+type opSpan struct {
+	span trace.Span
+}
+
+// startSpan inicia o span da operação op (nil, sem custo, sem WithTracer). O ctx retornado
+// carrega o span, para que operações internas fiquem aninhadas nele.
+func (r *Repository[T]) startSpan(ctx context.Context, op string) (context.Context, *opSpan) {
+	if r.cfg.tracer == nil {
+		return ctx, nil
+	}
+	ctx, span := r.cfg.tracer.Start(ctx, "monger."+op+" "+r.coll.Name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "mongodb"),
+			attribute.String("db.namespace", r.coll.Database().Name()),
+			attribute.String("db.collection.name", r.coll.Name()),
+			attribute.String("db.operation.name", op),
+		),
+	)
+	return ctx, &opSpan{span: span}
+}
+
+// end registra o erro (se houver) ou a quantidade de documentos (docs < 0: desconhecida) e
+// encerra o span.
+func (s *opSpan) end(err error, docs int64) {
+	if s == nil {
+		return
+	}
+	if err == nil && docs >= 0 {
+		s.span.SetAttributes(attribute.Int64("monger.documents", docs))
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// pageDocs conta os documentos de uma página (para o span).
+func pageDocs[T any](res *PagedResult[T]) int64 {
+	if res == nil {
+		return 0
+	}
+	return int64(len(res.Data))
+}
+
+// updatedDocs conta os documentos afetados por um update (para o span).
+func updatedDocs(res *UpdateResult) int64 {
+	if res == nil {
+		return 0
+	}
+	return res.Matched + res.Upserted
+}
+
+// bulkDocs conta os documentos afetados por um lote (para o span).
+func bulkDocs(res *BulkResult) int64 {
+	if res == nil {
+		return 0
+	}
+	return res.InsertedCount + res.MatchedCount + res.DeletedCount + res.UpsertedCount
+}
+
+// retryDocs conta os documentos afetados por um BulkWriteRetry (para o span).
+func retryDocs(res *BulkRetryResult) int64 {
+	if res == nil {
+		return 0
+	}
+	return res.InsertedCount + res.MatchedCount + res.DeletedCount + res.UpsertedCount
+}
+
+// found conta 1 documento se doc não for nil (para o span de operações de um documento).
+func found[R any](doc *R) int64 {
+	if doc == nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,99 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: trace_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes do tracing (WithTracer), com um tracer que registra os nomes dos
+	spans, sobre um servidor simulado.
+*/
+package monger
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingTracer guarda o nome de cada span iniciado.
+type recordingTracer struct {
+	noop.Tracer
+	names []string
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.names = append(t.names, name)
+	return t.Tracer.Start(ctx, name, opts...)
+}
+
+func TestTracerCoversOperations(t *testing.T) {
+	type item struct {
+		ID   string `bson:"_id"`
+		Name string `bson:"name"`
+	}
+	ctx := context.Background()
+	tracer := &recordingTracer{}
+	row := bson.D{{Key: "_id", Value: "i1"}, {Key: "name", Value: "a"}}
+
+	mockRepo(t, []Option{WithTracer(tracer)}, func(t *testing.T, mt *mtest.T, r *Repository[item]) {
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 3}))
+		if _, err := r.EstimatedCount(ctx); err != nil {
+			t.Fatal(err)
+		}
+		mt.AddMockResponses(cursorReply(mt, bson.D{{Key: "n", Value: 1}}))
+		if _, err := r.Exists(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+		mt.AddMockResponses(okReply(bson.E{Key: "values", Value: bson.A{"a", "b"}}))
+		if _, err := Distinct[string](ctx, r, "name", nil); err != nil {
+			t.Fatal(err)
+		}
+		mt.AddMockResponses(okReply(bson.E{Key: "n", Value: 2}))
+		if _, err := r.DeleteAll(ctx); err != nil {
+			t.Fatal(err)
+		}
+		mt.AddMockResponses(cursorReply(mt, row))
+		if _, err := AggregateAs[item](ctx, r, nil); err != nil {
+			t.Fatal(err)
+		}
+		// Iterate usa o mesmo cursor de Stream, mas com um único span
+		mt.AddMockResponses(cursorReply(mt, row))
+		if err := r.Iterate(ctx, nil, nil, func(*item) error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+		mt.AddMockResponses(cursorReply(mt, row))
+		for _, err := range r.Stream(ctx, nil, nil) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	want := []string{
+		"monger.EstimatedCount items",
+		"monger.Exists items",
+		"monger.Distinct items",
+		"monger.DeleteAll items",
+		"monger.AggregateAs items",
+		"monger.Iterate items",
+		"monger.Stream items",
+	}
+	if !reflect.DeepEqual(tracer.names, want) {
+		t.Errorf("spans = %v, esperado %v", tracer.names, want)
+	}
+}
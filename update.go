@@ -0,0 +1,250 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: update.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o UpdateBuilder: updates com vários operadores
+	($inc, $push, $pull, $unset, ...), para contadores e mutações de arrays
+	atômicas que o update parcial ($set) não expressa.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+)
+
+// --- UPDATE BUILDER ---
+// Monta um documento de update com vários operadores sem a sintaxe verbosa do BSON
+type UpdateBuilder struct {
+	u M
+}
+
+// Update cria um UpdateBuilder vazio.
+//
+// Exemplo de uso:
+//
+//	u := monger.Update().
+//	    Inc("views", 1).
+//	    Push("history", entry).
+//	    Unset("draft")
+func Update() *UpdateBuilder {
+	return &UpdateBuilder{u: M{}}
+}
+
+// op registra field: val no operador informado.
+func (b *UpdateBuilder) op(op, field string, val any) *UpdateBuilder {
+	fields, ok := b.u[op].(M)
+	if !ok {
+		fields = M{}
+		b.u[op] = fields
+	}
+	fields[field] = val
+	return b
+}
+
+// Set grava o valor no campo: {$set: {field: val}}
+func (b *UpdateBuilder) Set(field string, val any) *UpdateBuilder { return b.op("$set", field, val) }
+
+// Unset remove o campo: {$unset: {field: ""}}
+func (b *UpdateBuilder) Unset(field string) *UpdateBuilder { return b.op("$unset", field, "") }
+
+// Inc soma n ao campo (n negativo decrementa): {$inc: {field: n}}
+func (b *UpdateBuilder) Inc(field string, n any) *UpdateBuilder { return b.op("$inc", field, n) }
+
+// Mul multiplica o campo por n: {$mul: {field: n}}
+func (b *UpdateBuilder) Mul(field string, n any) *UpdateBuilder { return b.op("$mul", field, n) }
+
+// Push acrescenta val ao array: {$push: {field: val}}
+func (b *UpdateBuilder) Push(field string, val any) *UpdateBuilder { return b.op("$push", field, val) }
+
+// PushEach acrescenta todos os valores ao array: {$push: {field: {$each: vals}}}
+func (b *UpdateBuilder) PushEach(field string, vals any) *UpdateBuilder {
+	return b.op("$push", field, M{"$each": vals})
+}
+
+// Pull remove do array os elementos iguais a match ou que satisfazem a condição
+// (M ou *FilterBuilder, para arrays de subdocumentos): {$pull: {field: match}}
+func (b *UpdateBuilder) Pull(field string, match any) *UpdateBuilder {
+	if f, ok := match.(*FilterBuilder); ok {
+		match = f.Build()
+	}
+	return b.op("$pull", field, match)
+}
+
+// AddToSet acrescenta val ao array se ainda não estiver presente: {$addToSet: {field: val}}
+func (b *UpdateBuilder) AddToSet(field string, val any) *UpdateBuilder {
+	return b.op("$addToSet", field, val)
+}
+
+// Min grava val se for menor que o valor atual: {$min: {field: val}}
+func (b *UpdateBuilder) Min(field string, val any) *UpdateBuilder { return b.op("$min", field, val) }
+
+// Max grava val se for maior que o valor atual: {$max: {field: val}}
+func (b *UpdateBuilder) Max(field string, val any) *UpdateBuilder { return b.op("$max", field, val) }
+
+// Rename renomeia o campo: {$rename: {from: to}}
+func (b *UpdateBuilder) Rename(from, to string) *UpdateBuilder { return b.op("$rename", from, to) }
+
+// CurrentDate grava a data atual do servidor no campo: {$currentDate: {field: true}}
+func (b *UpdateBuilder) CurrentDate(field string) *UpdateBuilder {
+	return b.op("$currentDate", field, true)
+}
+
+// Build retorna o documento de update com todos os operadores.
+func (b *UpdateBuilder) Build() M {
+	return b.u
+}
+
+// UpdateByIDWith aplica o update do builder ao documento com o ID informado e retorna as
+// contagens do servidor, ou ErrNotFound se nenhum documento tiver o ID.
+//
+// Campos imutáveis (WithImmutableFields) são ignorados (ou rejeitados, com
+// WithRejectImmutable); com WithTimestamps, a data de atualização é gravada; com
+// WithVersioning, a versão é incrementada (o builder não pode alterá-la diretamente).
+//
+// Exemplo de uso:
+//
+//	_, err := posts.UpdateByIDWith(ctx, id, monger.Update().Inc("views", 1).AddToSet("tags", "go"))
+func (r *Repository[T]) UpdateByIDWith(ctx context.Context, id string, u *UpdateBuilder) (out *UpdateResult, err error) {
+	ctx, span := r.startSpan(ctx, "UpdateByIDWith")
+	defer func() { span.end(err, updatedDocs(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if err := r.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+	oid, err := r.cfg.parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	update, err := r.builtUpdate(u)
+	if err != nil {
+		return nil, err
+	}
+	r.logOp("UpdateByIDWith", func() M { return M{"filter": M{"_id": oid}, "update": update} })
+	res, err := r.coll.UpdateOne(ctx, M{"_id": oid}, update)
+	if err != nil {
+		return nil, writeError(err)
+	}
+	if res.MatchedCount == 0 {
+		return nil, ErrNotFound
+	}
+	return newUpdateResult(res), nil
+}
+
+// UpdateManyWith aplica o update do builder (mesmas regras do UpdateByIDWith) a todos os
+// documentos que satisfazem o filtro, e retorna as contagens do servidor.
+// O filtro é obrigatório e não pode ser vazio, para evitar atualizar a coleção inteira por engano.
+//
+// Exemplo de uso:
+//
+//	res, err := carts.UpdateManyWith(ctx, monger.Filter().Eq("items.sku", sku),
+//	    monger.Update().Pull("items", monger.Filter().Eq("sku", sku)))
+func (r *Repository[T]) UpdateManyWith(ctx context.Context, f *FilterBuilder, u *UpdateBuilder) (out *UpdateResult, err error) {
+	ctx, span := r.startSpan(ctx, "UpdateManyWith")
+	defer func() { span.end(err, updatedDocs(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if f == nil || len(f.Build()) == 0 {
+		return nil, fmt.Errorf("filtro é obrigatório para UpdateManyWith")
+	}
+	if err := r.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+	update, err := r.builtUpdate(u)
+	if err != nil {
+		return nil, err
+	}
+	r.logOp("UpdateManyWith", func() M { return M{"filter": f.Build(), "update": update} })
+	res, err := r.coll.UpdateMany(ctx, f.Build(), update)
+	if err != nil {
+		return nil, writeError(err)
+	}
+	return newUpdateResult(res), nil
+}
+
+// builtUpdate copia o update do builder aplicando as regras do repositório: remove (ou
+// rejeita) campos imutáveis e o _id, retira o campo de versão, grava a data de atualização
+// e incrementa a versão. Erro se não sobrar nenhuma operação.
+func (r *Repository[T]) builtUpdate(u *UpdateBuilder) (M, error) {
+	if u == nil {
+		return nil, fmt.Errorf("update não pode ser nil")
+	}
+	update := M{}
+	touched := false
+	for op, v := range u.Build() {
+		src, ok := v.(M)
+		if !ok {
+			return nil, fmt.Errorf("operador %s inválido: esperado documento", op)
+		}
+		fields := make(M, len(src))
+		for field, val := range src {
+			fields[field] = val
+		}
+		if op == "$rename" {
+			// o destino também não pode ser um campo protegido
+			targets := M{}
+			for _, to := range fields {
+				if s, ok := to.(string); ok {
+					targets[s] = nil
+				}
+			}
+			if err := r.stripImmutable(targets); err != nil {
+				return nil, err
+			}
+			for from, to := range fields {
+				if s, ok := to.(string); ok {
+					if _, kept := targets[s]; !kept {
+						delete(fields, from)
+					}
+				}
+			}
+		}
+		delete(fields, "_id")
+		if err := r.stripImmutable(fields); err != nil {
+			return nil, err
+		}
+		if f := r.cfg.versionField; f != "" {
+			delete(fields, f)
+		}
+		if _, ok := fields[r.cfg.updatedField]; ok {
+			touched = true
+		}
+		if len(fields) > 0 {
+			update[op] = fields
+		}
+	}
+	if len(update) == 0 {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	if r.cfg.updatedField != "" && !touched {
+		set, _ := update["$set"].(M)
+		if set == nil {
+			set = M{}
+			update["$set"] = set
+		}
+		r.touch(set)
+	}
+	if f := r.cfg.versionField; f != "" {
+		inc, _ := update["$inc"].(M)
+		if inc == nil {
+			inc = M{}
+			update["$inc"] = inc
+		}
+		inc[f] = 1
+	}
+	return update, nil
+}
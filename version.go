@@ -0,0 +1,201 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: version.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define o controle de concorrência otimista (WithVersioning):
+	escritas condicionadas ao número de versão do documento, com retentativa.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Transform executa um read-modify-write com lock otimista: carrega o documento pelo ID,
+// aplica fn sobre ele e grava o resultado (replace) somente se a versão do documento não
+// mudou desde a leitura, incrementando-a. Em caso de conflito (outro processo gravou antes),
+// o documento é recarregado e fn é aplicada de novo, até maxRetries retentativas.
+//
+// Requer WithVersioning. Retorna o documento gravado (com a nova versão), ErrNotFound se o
+// documento não existir, o erro de fn sem alteração (nada é gravado), ou um erro que
+// satisfaz errors.Is(err, ErrVersionConflict) se as retentativas se esgotarem.
+//
+// Como fn pode ser executada mais de uma vez, ela não deve ter efeitos colaterais fora do
+// documento. O replace grava o documento inteiro: campos que não existem em T são removidos;
+// campos imutáveis (WithImmutableFields) mantêm o valor original mesmo se fn os alterar.
+//
+// Exemplo de uso:
+//
+//	acc, err := accounts.Transform(ctx, id, func(a *Account) error {
+//	    if a.Balance < amount {
+//	        return ErrInsufficientFunds
+//	    }
+//	    a.Balance -= amount
+//	    a.History = append(a.History, entry)
+//	    return nil
+//	}, 5)
+func (r *Repository[T]) Transform(ctx context.Context, id string, fn func(*T) error, maxRetries int) (out *T, err error) {
+	ctx, span := r.startSpan(ctx, "Transform")
+	defer func() { span.end(err, found(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if err := r.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+	field := r.cfg.versionField
+	if field == "" {
+		return nil, fmt.Errorf("Transform requer WithVersioning")
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("fn não pode ser nil")
+	}
+	oid, err := r.cfg.parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		filter, err := r.scopeFilter(ctx, M{"_id": oid})
+		if err != nil {
+			return nil, err
+		}
+		raw, err := r.coll.FindOne(ctx, filter).Raw()
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, notFound(err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var doc T
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, wrapDecodeError(r.coll.Name(), raw, err)
+		}
+		if err := afterFind(ctx, &doc); err != nil {
+			return nil, err
+		}
+		if err := fn(&doc); err != nil {
+			return nil, err
+		}
+		if err := beforeUpdate(ctx, &doc); err != nil {
+			return nil, err
+		}
+
+		current, guard := r.versionGuard(raw)
+		replacement, err := toDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		replacement["_id"] = oid
+		if err := r.keepImmutable(replacement, raw); err != nil {
+			return nil, err
+		}
+		replacement[field] = current + 1
+		r.touch(replacement)
+
+		res, err := r.coll.ReplaceOne(ctx, andFilters(M{"_id": oid}, guard), replacement)
+		if err != nil {
+			return nil, writeError(err)
+		}
+		if res.MatchedCount == 0 {
+			continue // versão mudou (ou documento removido): recarrega
+		}
+
+		var saved T
+		if err := fromDocument(replacement, &saved); err != nil {
+			return nil, err
+		}
+		if err := afterFind(ctx, &saved); err != nil {
+			return nil, err
+		}
+		return &saved, nil
+	}
+	return nil, fmt.Errorf("%w: desistindo após %d tentativas", ErrVersionConflict, maxRetries+1)
+}
+
+// setUpdate monta o update {$set: set} e, com WithVersioning, incrementa a versão ($inc).
+// A versão nunca é gravada diretamente pelo $set; um $set vazio é omitido.
+func (r *Repository[T]) setUpdate(set M) M {
+	update := M{}
+	if f := r.cfg.versionField; f != "" {
+		delete(set, f)
+		update["$inc"] = M{f: 1}
+	}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	return update
+}
+
+// versionConflict é chamado quando um update com versão esperada não casou: retorna
+// ErrVersionConflict se algum documento satisfaz filter (sem a condição de versão, ou seja,
+// a versão mudou) ou nil se nenhum satisfaz.
+func (r *Repository[T]) versionConflict(ctx context.Context, filter M, expected any) error {
+	n, err := r.coll.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: versão esperada %v", ErrVersionConflict, expected)
+}
+
+// versionGuard lê a versão atual do documento e monta o filtro que garante que ela não mudou.
+// Documentos sem o campo (anteriores ao versionamento) são tratados como versão 0.
+func (r *Repository[T]) versionGuard(raw bson.Raw) (int64, M) {
+	field := r.cfg.versionField
+	val, err := raw.LookupErr(field)
+	if err != nil {
+		return 0, M{field: M{"$exists": false}}
+	}
+	var v any
+	if err := val.Unmarshal(&v); err != nil {
+		return 0, M{field: M{"$exists": false}}
+	}
+	current := asInt64(v)
+	return current, M{field: v}
+}
+
+// toDocument converte um model em documento M (respeitando as tags bson).
+func toDocument(model any) (M, error) {
+	raw, err := bson.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	var doc M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// fromDocument decodifica um documento M no model apontado por out.
+func fromDocument(doc M, out any) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, out)
+}
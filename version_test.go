@@ -0,0 +1,76 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: version_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes do controle de concorrência otimista (WithVersioning), sobre um
+	servidor simulado.
+*/
+package monger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestFindOneAndUpdateVersionGuard(t *testing.T) {
+	type job struct {
+		ID      string `bson:"_id"`
+		Status  string `bson:"status"`
+		Version int64  `bson:"version"`
+	}
+	type jobPatch struct {
+		Status  *string `bson:"status,omitempty"`
+		Version *int64  `bson:"version,omitempty"`
+	}
+	ctx := context.Background()
+	patch := &jobPatch{Status: Value("running"), Version: Value(int64(3))}
+
+	mockRepo(t, []Option{WithVersioning("version")}, func(t *testing.T, mt *mtest.T, r *Repository[job]) {
+		// Nenhum documento na versão 3, mas o documento existe: conflito
+		mt.AddMockResponses(
+			okReply(bson.E{Key: "value", Value: nil}),
+			cursorReply(mt, bson.D{{Key: "n", Value: 1}}),
+		)
+		_, err := r.FindOneAndUpdate(ctx, Filter().Eq("_id", "j1"), patch, true)
+		if !errors.Is(err, ErrVersionConflict) {
+			t.Fatalf("err = %v, esperado ErrVersionConflict", err)
+		}
+		cmd := sentCommand(t, mt)
+		if v, err := cmd.LookupErr("query", "$and", "1", "version"); err != nil || v.AsInt64() != 3 {
+			t.Errorf("filtro sem a versão esperada: %v", cmd.Lookup("query"))
+		}
+		if _, err := cmd.LookupErr("update", "$set", "version"); err == nil {
+			t.Errorf("a versão não deve ser gravada pelo $set: %v", cmd.Lookup("update"))
+		}
+		if inc, err := cmd.LookupErr("update", "$inc", "version"); err != nil || inc.AsInt64() != 1 {
+			t.Errorf("update sem $inc da versão: %v", cmd.Lookup("update"))
+		}
+		sentCommand(t, mt) // contagem da verificação de conflito
+
+		// Documento inexistente: ErrNotFound
+		mt.AddMockResponses(
+			okReply(bson.E{Key: "value", Value: nil}),
+			cursorReply(mt),
+		)
+		_, err = r.FindOneAndUpdate(ctx, Filter().Eq("_id", "j2"), patch, true)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("err = %v, esperado ErrNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: view.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define as buscas decodificadas em "views" (structs mais leves
+	que o model), com projeção derivada automaticamente das tags bson.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindAs busca os documentos que satisfazem o filtro e decodifica cada um em R, um struct
+// de visão (geralmente com menos campos que T).
+//
+// A projeção p (ex.: Select), quando informada, é usada como está. Sem p e com projectAuto
+// true, a projeção é derivada das tags bson de R (incluindo structs com ",inline"), de modo
+// que só os campos usados pela visão trafegam pela rede. Campos com tag "-" são ignorados e
+// structs aninhados são projetados por inteiro. Sem p e com projectAuto false, o documento
+// completo é buscado.
+//
+// Diferente do FindAll, o filtro é aplicado como montado (sem busca fuzzy em strings).
+//
+// Exemplo de uso:
+//
+//	type UserSummary struct {
+//	    ID   primitive.ObjectID `bson:"_id"`
+//	    Name string             `bson:"name"`
+//	}
+//
+//	list, err := monger.FindAs[UserSummary](ctx, users, monger.Filter().Eq("active", true), nil, true)
+//
+//	// projeção explícita
+//	list, err = monger.FindAs[UserSummary](ctx, users, nil, monger.Select("name"), false)
+func FindAs[R any, T any](ctx context.Context, r *Repository[T], f *FilterBuilder, p *ProjectBuilder, projectAuto bool) (out []R, err error) {
+	ctx, span := r.startSpan(ctx, "FindAs")
+	defer func() { span.end(err, int64(len(out))) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	filter, err := r.readFilter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find()
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
+	if sort := r.sortOrDefault(nil); sort != nil {
+		opts.SetSort(sort)
+		r.checkSortIndex(ctx, sort)
+	}
+	if proj := viewProjection[R](p, projectAuto); proj != nil {
+		opts.SetProjection(proj)
+	}
+
+	r.logOp("FindAs", func() M { return M{"filter": filter, "projection": opts.Projection, "sort": opts.Sort} })
+	cursor, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	return decodeCursor[R](ctx, cursor, r.coll.Name())
+}
+
+// FindOneAs busca um único documento com filtro (obrigatório) e o decodifica em R, como
+// FindAs (mesmas regras de p e projectAuto). Usa a ordenação padrão do repositório
+// (WithDefaultSort) para escolher o documento e retorna ErrNotFound quando nenhum documento
+// satisfaz o filtro.
+//
+// Exemplo de uso:
+//
+//	summary, err := monger.FindOneAs[UserSummary](ctx, users, monger.Filter().Eq("email", email), nil, true)
+//	if errors.Is(err, monger.ErrNotFound) {
+//	    // não cadastrado
+//	}
+func FindOneAs[R any, T any](ctx context.Context, r *Repository[T], f *FilterBuilder, p *ProjectBuilder, projectAuto bool) (out *R, err error) {
+	ctx, span := r.startSpan(ctx, "FindOneAs")
+	defer func() { span.end(err, found(out)) }()
+	ctx, cancel := r.cfg.withTimeout(ctx)
+	defer cancel()
+	if f == nil {
+		return nil, fmt.Errorf("filtro é obrigatório para FindOneAs; use FindAs para buscar múltiplos documentos")
+	}
+	filter, err := r.scopeFilter(ctx, f.Build())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.FindOne()
+	if c := r.cfg.collationFor(ctx); c != nil {
+		opts.SetCollation(c)
+	}
+	if sort := r.sortOrDefault(nil); sort != nil {
+		opts.SetSort(sort)
+		r.checkSortIndex(ctx, sort)
+	}
+	if proj := viewProjection[R](p, projectAuto); proj != nil {
+		opts.SetProjection(proj)
+	}
+
+	r.logOp("FindOneAs", func() M { return M{"filter": filter, "projection": opts.Projection, "sort": opts.Sort} })
+	out, err = withRetry(ctx, &r.cfg, true, func() (*R, error) {
+		return decodeSingle[R](ctx, r.coll.FindOne(ctx, filter, opts), r.coll.Name())
+	})
+	return out, notFound(err)
+}
+
+// viewProjection escolhe a projeção de FindAs e FindOneAs: a de p, se informada; senão, com
+// projectAuto, a derivada de R. Retorna nil quando não há projeção.
+func viewProjection[R any](p *ProjectBuilder, projectAuto bool) M {
+	if p != nil {
+		return p.Build()
+	}
+	if !projectAuto {
+		return nil
+	}
+	if proj := projectionFor(reflect.TypeOf((*R)(nil)).Elem()); len(proj) > 0 {
+		return proj
+	}
+	return nil
+}
+
+// projectionFor monta uma projeção de inclusão com os campos bson do struct t.
+// Retorna nil (sem projeção) se t não for struct ou tiver um mapa inline.
+func projectionFor(t reflect.Type) M {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	proj := M{}
+	if !collectProjection(t, proj) {
+		return nil
+	}
+	return proj
+}
+
+// collectProjection registra em proj o nome bson de cada campo exportado de t,
+// entrando em structs marcadas com ",inline". Retorna false se t tiver um mapa inline
+// (que recebe campos arbitrários), caso em que não é possível projetar.
+func collectProjection(t reflect.Type, proj M) bool {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name, inline := parseBsonTag(sf.Tag.Get("bson"))
+		if name == "-" {
+			continue
+		}
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if inline && ft.Kind() == reflect.Map {
+			return false
+		}
+		if inline && ft.Kind() == reflect.Struct {
+			if !collectProjection(ft, proj) {
+				return false
+			}
+			continue
+		}
+		if name == "" {
+			// mesmo padrão do driver: nome do campo em minúsculas
+			name = strings.ToLower(sf.Name)
+		}
+		proj[name] = 1
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: view_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes de FindAs e FindOneAs (views), sobre um servidor simulado.
+*/
+package monger
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type viewUser struct {
+	ID    string `bson:"_id"`
+	Name  string `bson:"name"`
+	Email string `bson:"email"`
+	Bio   string `bson:"bio"`
+}
+
+type viewSummary struct {
+	ID   string `bson:"_id"`
+	Name string `bson:"name"`
+}
+
+func TestFindAsProjection(t *testing.T) {
+	ctx := context.Background()
+	row := bson.D{{Key: "_id", Value: "u1"}, {Key: "name", Value: "Ana"}}
+
+	tests := []struct {
+		name        string
+		p           *ProjectBuilder
+		projectAuto bool
+		want        bson.D // nil: sem projeção
+	}{
+		{"derivada de R", nil, true, bson.D{{Key: "_id", Value: 1}, {Key: "name", Value: 1}}},
+		{"p tem prioridade", Select("name"), true, bson.D{{Key: "name", Value: 1}}},
+		{"sem p e sem projectAuto", nil, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo(t, nil, func(t *testing.T, mt *mtest.T, r *Repository[viewUser]) {
+				checkProjection := func(op string, cmd bson.Raw) {
+					t.Helper()
+					got, err := cmd.LookupErr("projection")
+					if tt.want == nil {
+						if err == nil {
+							t.Errorf("%s: projeção inesperada %v", op, got)
+						}
+						return
+					}
+					var m M
+					if err != nil || got.Unmarshal(&m) != nil || len(m) != len(tt.want) {
+						t.Fatalf("%s: projeção = %v, esperado %v", op, got, tt.want)
+					}
+					for _, e := range tt.want {
+						if _, ok := m[e.Key]; !ok {
+							t.Errorf("%s: projeção = %v, esperado %v", op, got, tt.want)
+						}
+					}
+				}
+
+				mt.AddMockResponses(cursorReply(mt, row))
+				list, err := FindAs[viewSummary](ctx, r, nil, tt.p, tt.projectAuto)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(list) != 1 || list[0].Name != "Ana" {
+					t.Errorf("FindAs = %v", list)
+				}
+				checkProjection("FindAs", sentCommand(t, mt))
+
+				mt.AddMockResponses(cursorReply(mt, row))
+				one, err := FindOneAs[viewSummary](ctx, r, Filter().Eq("_id", "u1"), tt.p, tt.projectAuto)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if one.Name != "Ana" {
+					t.Errorf("FindOneAs = %v", one)
+				}
+				checkProjection("FindOneAs", sentCommand(t, mt))
+			})
+		})
+	}
+}
+
+func TestFindAsReadPath(t *testing.T) {
+	ctx := context.Background()
+	row := bson.D{{Key: "_id", Value: "u1"}, {Key: "name", Value: "Ana"}}
+	stepDown := bson.D{{Key: "ok", Value: 0}, {Key: "code", Value: 189}, {Key: "errmsg", Value: "primary stepped down"}}
+	var logged []string
+	opts := []Option{
+		WithCollation(&options.Collation{Locale: "pt", Strength: 1}),
+		WithLogger(func(op string, _ M) { logged = append(logged, op) }),
+		WithRetry(2, time.Millisecond),
+	}
+
+	mockRepo(t, opts, func(t *testing.T, mt *mtest.T, r *Repository[viewUser]) {
+		// O driver já repete a leitura uma vez; a terceira tentativa vem do WithRetry
+		mt.AddMockResponses(stepDown, stepDown, cursorReply(mt, row))
+		if _, err := FindAs[viewSummary](ctx, r, nil, nil, true); err != nil {
+			t.Fatalf("FindAs não foi retentado: %v", err)
+		}
+		if loc := sentCommand(t, mt).Lookup("collation", "locale"); loc.StringValue() != "pt" {
+			t.Errorf("FindAs: collation = %v", loc)
+		}
+		sentCommand(t, mt) // retentativas
+		sentCommand(t, mt)
+
+		mt.AddMockResponses(stepDown, stepDown, cursorReply(mt, row))
+		if _, err := FindOneAs[viewSummary](ctx, r, Filter().Eq("_id", "u1"), nil, true); err != nil {
+			t.Fatalf("FindOneAs não foi retentado: %v", err)
+		}
+		if loc := sentCommand(t, mt).Lookup("collation", "locale"); loc.StringValue() != "pt" {
+			t.Errorf("FindOneAs: collation = %v", loc)
+		}
+
+		if want := []string{"FindAs", "FindOneAs"}; !reflect.DeepEqual(logged, want) {
+			t.Errorf("logger = %v, esperado %v", logged, want)
+		}
+	})
+}
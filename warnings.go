@@ -0,0 +1,180 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: warnings.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define os avisos de desenvolvimento (WithExplainWarnings):
+	verificações que apontam consultas que tendem a ficar lentas em produção,
+	como ordenações sem índice.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// indexCacheTTL é por quanto tempo a lista de índices fica em cache nas verificações.
+const indexCacheTTL = time.Minute
+
+// indexCache guarda as chaves dos índices da coleção e os avisos já emitidos.
+type indexCache struct {
+	mu       sync.Mutex
+	keys     []D
+	loadedAt time.Time
+	warned   map[string]bool
+}
+
+// warn envia um aviso para a função configurada em WithExplainWarnings; sem ela, para o logger
+// de WithLogger (operação "Warning", com a mensagem em payload["message"]) ou, sem logger,
+// para o log padrão.
+func (r *Repository[T]) warn(msg string) {
+	switch {
+	case r.cfg.warn != nil:
+		r.cfg.warn(msg)
+	case r.cfg.logger != nil:
+		r.logOp("Warning", func() M { return M{"message": msg} })
+	default:
+		log.Print(msg)
+	}
+}
+
+// checkSortIndex avisa (uma vez por ordenação) quando nenhum índice da coleção suporta sort,
+// o que obriga o servidor a ordenar em memória (lento e limitado a 32MB por padrão).
+// Só é executado com WithExplainWarnings.
+func (r *Repository[T]) checkSortIndex(ctx context.Context, sort D) {
+	if r.indexes == nil || len(sort) == 0 {
+		return
+	}
+	for _, e := range sort {
+		if _, ok := sortDirection(e.Value); !ok {
+			return // ordenação por $meta (textScore): não depende de índice comum
+		}
+	}
+	c := r.indexes
+	spec := describeSort(sort)
+	c.mu.Lock()
+	if c.warned[spec] {
+		c.mu.Unlock()
+		return
+	}
+	keys := c.keys
+	stale := keys == nil || time.Since(c.loadedAt) > indexCacheTTL
+	c.mu.Unlock()
+
+	if stale {
+		// Sem o lock, para não serializar as consultas ordenadas durante a ida ao servidor
+		var err error
+		if keys, err = r.listIndexKeys(ctx); err != nil {
+			return // verificação de desenvolvimento: falhas não interrompem a consulta
+		}
+		c.mu.Lock()
+		c.keys, c.loadedAt = keys, time.Now()
+		c.mu.Unlock()
+	}
+	for _, key := range keys {
+		if indexSupportsSort(key, sort) {
+			return
+		}
+	}
+	c.mu.Lock()
+	already := c.warned[spec]
+	c.warned[spec] = true
+	c.mu.Unlock()
+	if already {
+		return
+	}
+	r.warn(fmt.Sprintf("monger: a ordenação {%s} em %s não é suportada por nenhum índice (ordenação em memória, limitada a 32MB)", spec, r.coll.Name()))
+}
+
+// resetIndexCache descarta a lista de índices em cache (após criar ou remover um índice).
+func (r *Repository[T]) resetIndexCache() {
+	if r.indexes == nil {
+		return
+	}
+	r.indexes.mu.Lock()
+	r.indexes.keys = nil
+	r.indexes.mu.Unlock()
+}
+
+// listIndexKeys retorna o padrão de chaves de cada índice da coleção. Roda fora da sessão de
+// ctx (mantendo o prazo): listIndexes não é permitido em transações, e a falha abortaria a
+// transação do chamador.
+func (r *Repository[T]) listIndexKeys(ctx context.Context) ([]D, error) {
+	ctx, cancel := withoutSession(ctx)
+	defer cancel()
+	cursor, err := r.coll.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var specs []struct {
+		Key D `bson:"key"`
+	}
+	if err := cursor.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+	keys := make([]D, len(specs))
+	for i, s := range specs {
+		keys[i] = s.Key
+	}
+	return keys, nil
+}
+
+// indexSupportsSort indica se a ordenação é um prefixo das chaves do índice, na mesma direção
+// ou com todas as direções invertidas (o índice pode ser percorrido ao contrário).
+func indexSupportsSort(key D, sort D) bool {
+	if len(sort) > len(key) {
+		return false
+	}
+	forward, backward := true, true
+	for i, s := range sort {
+		if key[i].Key != s.Key {
+			return false
+		}
+		kd, ok := sortDirection(key[i].Value)
+		if !ok {
+			return false // índices text/hashed/2d não suportam ordenação
+		}
+		sd, _ := sortDirection(s.Value)
+		forward = forward && kd == sd
+		backward = backward && kd == -sd
+	}
+	return forward || backward
+}
+
+// sortDirection converte a direção de uma chave (1/-1 em qualquer tipo numérico) para int.
+func sortDirection(v any) (int, bool) {
+	switch n := asInt64(v); {
+	case n > 0:
+		return 1, true
+	case n < 0:
+		return -1, true
+	}
+	return 0, false
+}
+
+// describeSort formata uma ordenação como "createdAt: -1, name: 1".
+func describeSort(sort D) string {
+	parts := make([]string, len(sort))
+	for i, e := range sort {
+		parts[i] = fmt.Sprintf("%s: %v", e.Key, e.Value)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,118 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: warnings_test.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Testes dos avisos de desenvolvimento (WithExplainWarnings).
+*/
+package monger
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestIndexSupportsSort(t *testing.T) {
+	key := D{{Key: "customerId", Value: 1}, {Key: "createdAt", Value: -1}}
+	tests := []struct {
+		sort D
+		want bool
+	}{
+		{D{{Key: "customerId", Value: 1}}, true},
+		{D{{Key: "customerId", Value: 1}, {Key: "createdAt", Value: -1}}, true},
+		{D{{Key: "customerId", Value: -1}, {Key: "createdAt", Value: 1}}, true}, // percorrido ao contrário
+		{D{{Key: "customerId", Value: 1}, {Key: "createdAt", Value: 1}}, false},
+		{D{{Key: "createdAt", Value: -1}}, false},
+	}
+	for _, tt := range tests {
+		if got := indexSupportsSort(key, tt.sort); got != tt.want {
+			t.Errorf("indexSupportsSort(%s) = %v, esperado %v", describeSort(tt.sort), got, tt.want)
+		}
+	}
+}
+
+func TestExplainWarningsUseLogger(t *testing.T) {
+	type user struct {
+		ID        string `bson:"_id"`
+		LastLogin int64  `bson:"lastLogin"`
+	}
+	var warnings []string
+	logger := func(op string, payload M) {
+		if op == "Warning" {
+			warnings = append(warnings, payload["message"].(string))
+		}
+	}
+	opts := []Option{WithExplainWarnings(nil), WithLogger(logger)}
+
+	mockRepo(t, opts, func(t *testing.T, mt *mtest.T, r *Repository[user]) {
+		mt.AddMockResponses(
+			cursorReply(mt, bson.D{{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}}, {Key: "name", Value: "_id_"}}),
+			cursorReply(mt, bson.D{{Key: "_id", Value: "u1"}}),
+		)
+		sort := D{{Key: "lastLogin", Value: -1}}
+		if _, err := r.FindOne(context.Background(), Filter().Eq("active", true), nil, sort); err != nil {
+			t.Fatal(err)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "lastLogin: -1") {
+			t.Errorf("avisos no logger = %v, esperado um aviso da ordenação {lastLogin: -1}", warnings)
+		}
+	})
+}
+
+func TestExplainWarningsOutsideTransaction(t *testing.T) {
+	type user struct {
+		ID        string `bson:"_id"`
+		LastLogin int64  `bson:"lastLogin"`
+	}
+	opts := []Option{WithExplainWarnings(func(string) {})}
+
+	mockRepo(t, opts, func(t *testing.T, mt *mtest.T, r *Repository[user]) {
+		sess, err := mt.Client.StartSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sess.EndSession(context.Background())
+		if err := sess.StartTransaction(); err != nil {
+			t.Fatal(err)
+		}
+		mt.AddMockResponses(
+			cursorReply(mt, bson.D{{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}}, {Key: "name", Value: "_id_"}}),
+			cursorReply(mt, bson.D{{Key: "_id", Value: "u1"}}),
+		)
+		err = mongo.WithSession(context.Background(), sess, func(sc mongo.SessionContext) error {
+			_, err := r.FindOne(sc, Filter().Eq("active", true), nil, D{{Key: "lastLogin", Value: -1}})
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// listIndexes roda fora da transação; a consulta, dentro dela
+		list := sentCommand(t, mt)
+		if _, err := list.LookupErr("listIndexes"); err != nil {
+			t.Fatalf("primeiro comando = %v, esperado listIndexes", list)
+		}
+		if _, err := list.LookupErr("txnNumber"); err == nil {
+			t.Errorf("listIndexes enviado dentro da transação: %v", list)
+		}
+		if _, err := sentCommand(t, mt).LookupErr("txnNumber"); err != nil {
+			t.Error("a consulta deveria continuar na transação")
+		}
+	})
+}
@@ -0,0 +1,227 @@
+/*
+Monger — utilitários para MongoDB em Go
+
+Arquivo: watch.go
+Módulo: github.com/zdekdev/monger
+Pacote: monger
+
+Autor: Melquizedeque Lobo
+Copyright (c) 2025
+Licença: MIT (veja o arquivo LICENSE na raiz do projeto)
+SPDX-License-Identifier: MIT
+
+Repositório: https://github.com/zdekdev/monger
+
+Descrição:
+
+	Este arquivo define os change streams tipados (Watch): eventos de
+	inserção, atualização e remoção com o documento decodificado em T e
+	suporte a retomada por resume token.
+*/
+package monger
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WatchOption configura uma chamada de Watch.
+type WatchOption func(*options.ChangeStreamOptions)
+
+// WatchResumeAfter retoma o change stream logo após o evento do token (ChangeEvent.ResumeToken
+// ou ChangeStream.ResumeToken), para continuar de onde o consumidor parou.
+func WatchResumeAfter(token bson.Raw) WatchOption {
+	return func(o *options.ChangeStreamOptions) { o.SetResumeAfter(token) }
+}
+
+// WatchStartAfter é como WatchResumeAfter, mas também aceita o token de um evento de
+// invalidação (ex.: a coleção foi removida e recriada).
+func WatchStartAfter(token bson.Raw) WatchOption {
+	return func(o *options.ChangeStreamOptions) { o.SetStartAfter(token) }
+}
+
+// WatchFullDocument faz os eventos de update trazerem o documento atual completo
+// (fullDocument: updateLookup), e não só os campos alterados.
+func WatchFullDocument() WatchOption {
+	return func(o *options.ChangeStreamOptions) { o.SetFullDocument(options.UpdateLookup) }
+}
+
+// WatchBeforeChange faz os eventos de update, replace e delete trazerem o documento como era
+// antes da alteração, quando disponível. Requer MongoDB 6.0+ com changeStreamPreAndPostImages
+// habilitado na coleção; sem isso, FullDocumentBeforeChange vem nil.
+func WatchBeforeChange() WatchOption {
+	return func(o *options.ChangeStreamOptions) { o.SetFullDocumentBeforeChange(options.WhenAvailable) }
+}
+
+// ChangeEvent é um evento do change stream, com os documentos decodificados em T.
+type ChangeEvent[T any] struct {
+	OperationType string              // insert, update, replace, delete, invalidate, ...
+	ID            string              // _id do documento alterado (como nos métodos ...ByID)
+	DocumentKey   M                   // chave do documento (_id e, em coleções sharded, a shard key)
+	ResumeToken   bson.Raw            // token para retomar logo após este evento
+	ClusterTime   primitive.Timestamp // momento da operação no cluster
+
+	FullDocument             *T // documento inserido/substituído (ou atual, com WatchFullDocument)
+	FullDocumentBeforeChange *T // documento antes da alteração (WatchBeforeChange)
+
+	UpdatedFields M        // campos alterados por um update (caminhos pontuados)
+	RemovedFields []string // campos removidos por um update
+}
+
+// ChangeStream percorre os eventos de Watch. Use como um cursor: Next, Event, Err e Close.
+type ChangeStream[T any] struct {
+	repo  *Repository[T]
+	cs    *mongo.ChangeStream
+	event *ChangeEvent[T]
+	err   error
+}
+
+// Watch abre um change stream na coleção do repositório. pipeline (opcional) são estágios
+// aplicados aos eventos (ex.: $match em operationType). Cada evento tem os documentos
+// decodificados em T (com AfterFind).
+//
+// Next bloqueia até o próximo evento; cancelar o ctx passado a Next faz ele retornar false
+// (e Err retorna o erro do ctx), o que permite um desligamento limpo. Sempre chame Close.
+// Para retomar após reiniciar, persista ChangeEvent.ResumeToken e passe-o em WatchResumeAfter.
+//
+// Requer replica set ou cluster sharded. Os eventos não passam pelas restrições do
+// repositório: com WithRowSecurity, Watch retorna erro; com WithSoftDelete, marcar um
+// documento como excluído chega como um evento de update.
+//
+// Exemplo de uso:
+//
+//	stream, err := orders.Watch(ctx, []monger.M{
+//	    {"$match": monger.M{"operationType": monger.M{"$in": []string{"insert", "update"}}}},
+//	}, monger.WatchFullDocument(), monger.WatchResumeAfter(lastToken))
+//	if err != nil {
+//	    return err
+//	}
+//	defer stream.Close(context.Background())
+//	for stream.Next(ctx) {
+//	    ev := stream.Event()
+//	    notify(ev.OperationType, ev.FullDocument)
+//	    saveToken(ev.ResumeToken)
+//	}
+//	return stream.Err()
+func (r *Repository[T]) Watch(ctx context.Context, pipeline []M, opts ...WatchOption) (stream *ChangeStream[T], err error) {
+	ctx, span := r.startSpan(ctx, "Watch")
+	defer func() { span.end(err, -1) }()
+	if r.cfg.rowSecurity != nil {
+		return nil, fmt.Errorf("Watch não suporta WithRowSecurity: os eventos não podem ser filtrados pelas restrições do repositório")
+	}
+	if pipeline == nil {
+		pipeline = []M{}
+	}
+	o := options.ChangeStream()
+	for _, opt := range opts {
+		opt(o)
+	}
+	cs, err := r.coll.Watch(ctx, pipeline, o)
+	if err != nil {
+		return nil, err
+	}
+	return &ChangeStream[T]{repo: r, cs: cs}, nil
+}
+
+// changeEventDoc é o formato bruto de um evento do change stream.
+type changeEventDoc struct {
+	ID                       bson.Raw            `bson:"_id"`
+	OperationType            string              `bson:"operationType"`
+	DocumentKey              M                   `bson:"documentKey"`
+	ClusterTime              primitive.Timestamp `bson:"clusterTime"`
+	FullDocument             bson.RawValue       `bson:"fullDocument"`
+	FullDocumentBeforeChange bson.RawValue       `bson:"fullDocumentBeforeChange"`
+	UpdateDescription        *struct {
+		UpdatedFields M        `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+// Next aguarda o próximo evento e o decodifica. Retorna false quando o stream termina, o ctx
+// é cancelado ou ocorre um erro (consulte Err).
+func (s *ChangeStream[T]) Next(ctx context.Context) bool {
+	s.event = nil
+	if s.err != nil || !s.cs.Next(ctx) {
+		return false
+	}
+	ev, err := s.decode(ctx, s.cs.Current)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	s.event = ev
+	return true
+}
+
+// Event retorna o evento lido pelo último Next.
+func (s *ChangeStream[T]) Event() *ChangeEvent[T] {
+	return s.event
+}
+
+// Err retorna o erro que interrompeu o stream (inclusive o cancelamento do ctx), ou nil.
+func (s *ChangeStream[T]) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.cs.Err()
+}
+
+// ResumeToken retorna o token do último evento lido (ou do ponto atual do stream), para
+// persistir e retomar com WatchResumeAfter.
+func (s *ChangeStream[T]) ResumeToken() bson.Raw {
+	return s.cs.ResumeToken()
+}
+
+// Close encerra o change stream no servidor.
+func (s *ChangeStream[T]) Close(ctx context.Context) error {
+	return s.cs.Close(ctx)
+}
+
+// decode converte um evento bruto em ChangeEvent, decodificando os documentos em T.
+func (s *ChangeStream[T]) decode(ctx context.Context, raw bson.Raw) (*ChangeEvent[T], error) {
+	var doc changeEventDoc
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	ev := &ChangeEvent[T]{
+		OperationType: doc.OperationType,
+		DocumentKey:   doc.DocumentKey,
+		ResumeToken:   doc.ID,
+		ClusterTime:   doc.ClusterTime,
+	}
+	if id, ok := doc.DocumentKey["_id"]; ok {
+		ev.ID = s.repo.cfg.idString(id)
+	}
+	if doc.UpdateDescription != nil {
+		ev.UpdatedFields = doc.UpdateDescription.UpdatedFields
+		ev.RemovedFields = doc.UpdateDescription.RemovedFields
+	}
+	var err error
+	if ev.FullDocument, err = s.decodeDocument(ctx, doc.FullDocument); err != nil {
+		return nil, err
+	}
+	if ev.FullDocumentBeforeChange, err = s.decodeDocument(ctx, doc.FullDocumentBeforeChange); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// decodeDocument decodifica um documento do evento em T (nil se ausente ou null).
+func (s *ChangeStream[T]) decodeDocument(ctx context.Context, val bson.RawValue) (*T, error) {
+	if val.Type != bson.TypeEmbeddedDocument {
+		return nil, nil
+	}
+	out := new(T)
+	if err := val.Unmarshal(out); err != nil {
+		return nil, wrapDecodeError(s.repo.coll.Name(), val.Document(), err)
+	}
+	if err := afterFind(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}